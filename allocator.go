@@ -0,0 +1,197 @@
+package hierarchyid
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"gorm.io/gorm"
+)
+
+// Returned when an allocator cannot find a level value for a new sibling.
+var ErrNoSiblingValue = errors.New("hierarchyid: no value available for a new sibling")
+
+// SiblingAllocator defines the numbering policy used to create new children of a node.
+type SiblingAllocator interface {
+	// Get the level value for a new child, siblings contains the values already used by the other children (in any order).
+	Next(siblings []int64) (int64, error)
+}
+
+// Dense numbering, children are numbered 1, 2, 3, etc.
+type DenseAllocator struct{}
+
+// Next implements the SiblingAllocator interface, returns the value after the largest sibling.
+func (DenseAllocator) Next(siblings []int64) (int64, error) {
+	if len(siblings) == 0 {
+		return 1, nil
+	}
+
+	return checkSiblingValue(maxValue(siblings) + 1)
+}
+
+// Sparse numbering, children are numbered with a gap (e.g. 10, 20, 30) so later inserts between siblings do not need to renumber.
+type SparseAllocator struct {
+	// Gap between siblings (10 if not set).
+	Gap int64
+}
+
+// Next implements the SiblingAllocator interface, returns the next multiple of the gap after the largest sibling.
+func (a SparseAllocator) Next(siblings []int64) (int64, error) {
+	var gap = a.Gap
+	if gap <= 0 {
+		gap = 10
+	}
+
+	if len(siblings) == 0 {
+		return gap, nil
+	}
+
+	var max = maxValue(siblings)
+	var next = max - max%gap + gap
+	if max < 0 && max%gap != 0 {
+		next -= gap
+	}
+
+	return checkSiblingValue(next)
+}
+
+// Prepend numbering, new children are placed before all existing siblings (1, 0, -1, -2, etc).
+type PrependAllocator struct{}
+
+// Next implements the SiblingAllocator interface, returns the value before the smallest sibling.
+func (PrependAllocator) Next(siblings []int64) (int64, error) {
+	if len(siblings) == 0 {
+		return 1, nil
+	}
+
+	return checkSiblingValue(minValue(siblings) - 1)
+}
+
+// Compact numbering, uses the free value with the smallest encoded size.
+//
+// Values from 0 to 3 use 5 bits, 4 to 7 use 6 bits, etc. The order of the children is not preserved.
+type CompactAllocator struct{}
+
+// Next implements the SiblingAllocator interface, returns the smallest free value of the shortest pattern.
+func (CompactAllocator) Next(siblings []int64) (int64, error) {
+	var used = map[int64]bool{}
+	for _, s := range siblings {
+		used[s] = true
+	}
+
+	var patterns = make([]*HierarchyIdPattern, len(Patterns))
+	for i := range Patterns {
+		patterns[i] = &Patterns[i]
+	}
+
+	sort.SliceStable(patterns, func(a, b int) bool {
+		return len(patterns[a].Pattern) < len(patterns[b].Pattern)
+	})
+
+	for _, p := range patterns {
+		for v := p.Min; v <= p.Max; v++ {
+			if !used[v] {
+				return v, nil
+			}
+		}
+	}
+
+	return 0, ErrNoSiblingValue
+}
+
+// Check that a value can be encoded.
+func checkSiblingValue(value int64) (int64, error) {
+	if findPattern(value) == nil {
+		return 0, ErrNoSiblingValue
+	}
+
+	return value, nil
+}
+
+// Get the largest value of a list.
+func maxValue(values []int64) int64 {
+	var max = values[0]
+	for _, v := range values[1:] {
+		if v > max {
+			max = v
+		}
+	}
+
+	return max
+}
+
+// Get the smallest value of a list.
+func minValue(values []int64) int64 {
+	var min = values[0]
+	for _, v := range values[1:] {
+		if v < min {
+			min = v
+		}
+	}
+
+	return min
+}
+
+// Get the child of this hierarchyid with the level value provided.
+func (j *HierarchyId) Child(level int64) HierarchyId {
+	return HierarchyId{Data: Join(j.Data, []int64{level})}
+}
+
+// Create a new child of a parent.
+//
+// Siblings are the existing nodes of the tree, only the direct children of the parent are considered.
+func NewChild(parent HierarchyId, siblings []HierarchyId, alloc SiblingAllocator) (HierarchyId, error) {
+	var children, err = NewChildren(parent, siblings, 1, alloc)
+	if err != nil {
+		return HierarchyId{}, err
+	}
+
+	return children[0], nil
+}
+
+// Create multiple new children of a parent, used to import a batch of nodes.
+//
+// Siblings are the existing nodes of the tree, only the direct children of the parent are considered.
+func NewChildren(parent HierarchyId, siblings []HierarchyId, n int, alloc SiblingAllocator) ([]HierarchyId, error) {
+	var values = []int64{}
+	for _, s := range siblings {
+		if len(s.Data) == len(parent.Data)+1 && s.IsDescendantOf(parent) {
+			values = append(values, s.Data[len(s.Data)-1])
+		}
+	}
+
+	var children = make([]HierarchyId, 0, n)
+	for i := 0; i < n; i++ {
+		var value, err = alloc.Next(values)
+		if err != nil {
+			return nil, err
+		}
+
+		values = append(values, value)
+		children = append(children, parent.Child(value))
+	}
+
+	return children, nil
+}
+
+// Get the hierarchyid for a new child of a parent stored in the table of the model.
+//
+// The children already stored (including soft deleted rows) are read from the database and the allocator decides the value of the new child.
+func NextChild(ctx context.Context, db *gorm.DB, model any, parent HierarchyId, alloc SiblingAllocator) (HierarchyId, error) {
+	var t, err = newTree(ctx, db, model)
+	if err != nil {
+		return HierarchyId{}, err
+	}
+
+	values, err := t.childValues(t.db, parent)
+	if err != nil {
+		return HierarchyId{}, err
+	}
+
+	value, err := alloc.Next(values)
+	if err != nil {
+		return HierarchyId{}, err
+	}
+
+	return parent.Child(value), nil
+}
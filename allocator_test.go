@@ -0,0 +1,114 @@
+package hierarchyid
+
+import (
+	"slices"
+	"testing"
+)
+
+type TestAllocatorStruct struct {
+	alloc    SiblingAllocator
+	siblings []int64
+	output   []int64
+}
+
+var TestAllocatorData []TestAllocatorStruct = []TestAllocatorStruct{
+	{DenseAllocator{}, []int64{}, []int64{1, 2, 3}},
+	{DenseAllocator{}, []int64{7, 2}, []int64{8, 9}},
+	{SparseAllocator{}, []int64{}, []int64{10, 20, 30}},
+	{SparseAllocator{Gap: 100}, []int64{150}, []int64{200, 300}},
+	{SparseAllocator{Gap: 10}, []int64{-15}, []int64{-10, 0, 10}},
+	{PrependAllocator{}, []int64{}, []int64{1, 0, -1}},
+	{PrependAllocator{}, []int64{3, 5}, []int64{2, 1}},
+	{CompactAllocator{}, []int64{}, []int64{0, 1, 2, 3, 4}},
+	{CompactAllocator{}, []int64{0, 2, 3}, []int64{1, 4, 5}},
+}
+
+func TestAllocator(t *testing.T) {
+	for _, d := range TestAllocatorData {
+		var siblings = slices.Clone(d.siblings)
+		var result = []int64{}
+
+		for range d.output {
+			value, err := d.alloc.Next(siblings)
+			if err != nil {
+				t.Errorf("Error allocating with %T: %v", d.alloc, err)
+			}
+
+			siblings = append(siblings, value)
+			result = append(result, value)
+		}
+
+		if !slices.Equal(result, d.output) {
+			t.Errorf("Expected %T with %v to return %v, got %v", d.alloc, d.siblings, d.output, result)
+		}
+	}
+}
+
+func TestAllocatorRange(t *testing.T) {
+	var max = Patterns[1].Max
+
+	_, err := DenseAllocator{}.Next([]int64{max})
+	if err != ErrNoSiblingValue {
+		t.Errorf("Expected no value after %v, got %v", max, err)
+	}
+
+	_, err = PrependAllocator{}.Next([]int64{Patterns[0].Min})
+	if err != ErrNoSiblingValue {
+		t.Errorf("Expected no value before %v, got %v", Patterns[0].Min, err)
+	}
+}
+
+func TestCompactAllocatorSize(t *testing.T) {
+	var siblings = []int64{}
+	var last = 0
+
+	// Each value should use the shortest pattern that still has free values
+	for i := 0; i < 200; i++ {
+		value, err := CompactAllocator{}.Next(siblings)
+		if err != nil {
+			t.Fatal("Error allocating", err)
+		}
+
+		var bits = len(findPattern(value).Pattern)
+		if bits < last {
+			t.Errorf("Expected %v to use at least %v bits, got %v", value, last, bits)
+		}
+		last = bits
+
+		for _, p := range Patterns {
+			if len(p.Pattern) < bits {
+				for v := p.Min; v <= p.Max; v++ {
+					if !slices.Contains(siblings, v) {
+						t.Fatalf("Expected %v (%v bits) to be used before %v (%v bits)", v, len(p.Pattern), value, bits)
+					}
+				}
+			}
+		}
+
+		siblings = append(siblings, value)
+	}
+}
+
+func TestNewChildren(t *testing.T) {
+	var parent = HierarchyId{Data: []int64{1, 2}}
+	var siblings = []HierarchyId{
+		{Data: []int64{1, 2, 4}},
+		{Data: []int64{1, 2, 6}},
+		{Data: []int64{1, 2, 9, 1}},
+		{Data: []int64{1, 3, 20}},
+	}
+
+	children, err := NewChildren(parent, siblings, 2, DenseAllocator{})
+	if err != nil {
+		t.Fatal("Error creating children", err)
+	}
+
+	if len(children) != 2 || !slices.Equal(children[0].Data, []int64{1, 2, 7}) || !slices.Equal(children[1].Data, []int64{1, 2, 8}) {
+		t.Errorf("Expected /1/2/7/ and /1/2/8/, got %v", children)
+	}
+
+	child, err := NewChild(parent, nil, SparseAllocator{})
+	if err != nil || !slices.Equal(child.Data, []int64{1, 2, 10}) {
+		t.Errorf("Expected /1/2/10/, got %v %v", child, err)
+	}
+}
@@ -0,0 +1,201 @@
+// Command hierarchyid encodes, decodes and inspects SQL Server hierarchyid values.
+//
+// Values can be provided as a path (e.g. '/1/2/754/') or as a SQL Server hex literal (e.g. '0x5B7A9150').
+//
+//	hierarchyid encode /1/2/754/
+//	hierarchyid decode 0x5B7A9150
+//	hierarchyid explain 0x5B7A9150
+//	hierarchyid compare /1/2/ /1/3/
+//	hierarchyid ancestors /1/2/754/
+//	hierarchyid reparent /1/2/57/8/ /1/2/ /1/3/
+//	hierarchyid tree -dsn "sqlserver://..." -table nodes -column path -label name
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+
+	"github.com/tentone/hierarchyid"
+)
+
+// Subcommand of the tool.
+type command struct {
+	// Arguments description presented in the usage.
+	usage string
+
+	// Number of arguments expected, -1 if the command parses its own flags.
+	args int
+
+	// Run the command with the provided arguments.
+	run func(w io.Writer, args []string) error
+}
+
+var commands = map[string]command{
+	"encode":    {"<path>", 1, encode},
+	"decode":    {"<hex>", 1, decode},
+	"explain":   {"<hex|path>", 1, explain},
+	"compare":   {"<a> <b>", 2, compare},
+	"ancestors": {"<hex|path>", 1, ancestors},
+	"reparent":  {"<hex|path> <old ancestor> <new ancestor>", 3, reparent},
+	"tree":      {"-dsn <dsn> -table <table> [-column path] [-label name] [-root /] [-depth n] [-format box]", -1, tree},
+}
+
+// Order in which commands are presented in the usage.
+var commandNames = []string{"encode", "decode", "explain", "compare", "ancestors", "reparent", "tree"}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage(os.Stderr)
+		os.Exit(2)
+	}
+
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n", os.Args[1])
+		usage(os.Stderr)
+		os.Exit(2)
+	}
+
+	var args = os.Args[2:]
+	if cmd.args >= 0 && len(args) != cmd.args {
+		fmt.Fprintf(os.Stderr, "usage: hierarchyid %s %s\n", os.Args[1], cmd.usage)
+		os.Exit(2)
+	}
+
+	err := cmd.run(os.Stdout, args)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+// Print the list of available commands.
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage: hierarchyid <command> [arguments]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "commands:")
+	for _, name := range commandNames {
+		fmt.Fprintf(w, "  %-10s %s\n", name, commands[name].usage)
+	}
+}
+
+// Parse a hierarchyid provided as path or hex literal.
+func parse(arg string) (hierarchyid.HierarchyId, error) {
+	var h = hierarchyid.HierarchyId{}
+
+	err := h.Scan(arg)
+	if err != nil {
+		return h, fmt.Errorf("invalid hierarchyid %q: %w", arg, err)
+	}
+
+	return h, nil
+}
+
+// Print the hex literal of a path.
+func encode(w io.Writer, args []string) error {
+	data, err := hierarchyid.FromString(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid path %q: %w", args[0], err)
+	}
+
+	str, err := hierarchyid.ToHex(data)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(w, str)
+	return nil
+}
+
+// Print the path of a hex literal.
+func decode(w io.Writer, args []string) error {
+	data, err := hierarchyid.FromHex(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid hex %q: %w", args[0], err)
+	}
+
+	fmt.Fprintln(w, hierarchyid.ToString(data))
+	return nil
+}
+
+// Print the bit span and pattern used by each level.
+func explain(w io.Writer, args []string) error {
+	h, err := parse(args[0])
+	if err != nil {
+		return err
+	}
+
+	data, err := h.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	levels, err := hierarchyid.Explain(data)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "path  %s\nhex   %X\nbits  %b\n", h, h, h)
+	for i, level := range levels {
+		fmt.Fprintf(w, "\nlevel %d: %d\n", i+1, level.Value)
+		fmt.Fprintf(w, "  bits     %d-%d %s\n", level.Start, level.End-1, level.Bits)
+		fmt.Fprintf(w, "  pattern  %s\n", level.Pattern.Pattern)
+		fmt.Fprintf(w, "  range    %d to %d\n", level.Pattern.Min, level.Pattern.Max)
+	}
+
+	return nil
+}
+
+// Print -1, 0 or 1 according to the depth-first order of two hierarchyids.
+func compare(w io.Writer, args []string) error {
+	a, err := parse(args[0])
+	if err != nil {
+		return err
+	}
+
+	b, err := parse(args[1])
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(w, strconv.Itoa(hierarchyid.CompareDepthFirst(a.Data, b.Data)))
+	return nil
+}
+
+// Print all ancestors from the first level to the direct parent, the root '/' is not printed.
+func ancestors(w io.Writer, args []string) error {
+	h, err := parse(args[0])
+	if err != nil {
+		return err
+	}
+
+	for _, a := range h.GetAncestors() {
+		fmt.Fprintln(w, a.String())
+	}
+
+	return nil
+}
+
+// Print the value of a node when moved from an ancestor to another.
+func reparent(w io.Writer, args []string) error {
+	var ids = make([]hierarchyid.HierarchyId, len(args))
+	for i, arg := range args {
+		h, err := parse(arg)
+		if err != nil {
+			return err
+		}
+		ids[i] = h
+	}
+
+	if !slices.Equal(ids[0].Data, ids[1].Data) && !ids[0].IsDescendantOf(ids[1]) {
+		return errors.New(ids[0].String() + " is not a descendant of " + ids[1].String())
+	}
+
+	var result = ids[0].GetReparentedValue(ids[1], ids[2])
+	fmt.Fprintln(w, result.String())
+	return nil
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+type TestCommandStruct struct {
+	// Command run
+	name string
+
+	// Arguments of the command
+	args []string
+
+	// Expected output
+	output string
+
+	// The command is expected to fail
+	fail bool
+}
+
+var TestCommandData = []TestCommandStruct{
+	{name: "encode", args: []string{"/1/2/754/"}, output: "0x5B7A9150\n"},
+	{name: "encode", args: []string{"/"}, output: "0x\n"},
+	{name: "encode", args: []string{"/a/"}, fail: true},
+	{name: "decode", args: []string{"0x5B7A9150"}, output: "/1/2/754/\n"},
+	{name: "decode", args: []string{"0x"}, output: "/\n"},
+	{name: "decode", args: []string{"0xZZ"}, fail: true},
+	{name: "explain", args: []string{"/1/"}, output: "path  /1/\nhex   0x58\nbits  01011\n\nlevel 1: 1\n  bits     0-4 01011\n  pattern  01xxT\n  range    0 to 3\n"},
+	{name: "explain", args: []string{"0x58"}, output: "path  /1/\nhex   0x58\nbits  01011\n\nlevel 1: 1\n  bits     0-4 01011\n  pattern  01xxT\n  range    0 to 3\n"},
+	{name: "explain", args: []string{"/a/"}, fail: true},
+	{name: "compare", args: []string{"/1/2/", "/1/3/"}, output: "-1\n"},
+	{name: "compare", args: []string{"/1/3/", "/1/2/"}, output: "1\n"},
+	{name: "compare", args: []string{"/1/2/", "0x5B40"}, output: "0\n"},
+	{name: "compare", args: []string{"/1/", "/1/2/"}, output: "-1\n"},
+	{name: "compare", args: []string{"/1/", "/a/"}, fail: true},
+	{name: "ancestors", args: []string{"/1/2/754/"}, output: "/1/\n/1/2/\n"},
+	{name: "ancestors", args: []string{"/1/"}, output: ""},
+	{name: "reparent", args: []string{"/1/2/57/8/", "/1/2/", "/1/3/"}, output: "/1/3/57/8/\n"},
+	{name: "reparent", args: []string{"/1/2/", "/1/2/", "/3/"}, output: "/3/\n"},
+	{name: "reparent", args: []string{"/1/", "/2/", "/3/"}, fail: true},
+	{name: "reparent", args: []string{"/1/", "/1/2/", "/3/"}, fail: true},
+}
+
+func TestCommands(t *testing.T) {
+	for _, d := range TestCommandData {
+		var buffer = &bytes.Buffer{}
+
+		err := commands[d.name].run(buffer, d.args)
+		if d.fail {
+			if err == nil {
+				t.Errorf("Expected %s %v to fail, got %q", d.name, d.args, buffer.String())
+			}
+			continue
+		}
+
+		if err != nil || buffer.String() != d.output {
+			t.Errorf("Expected %s %v to print %q, got %q %v", d.name, d.args, d.output, buffer.String(), err)
+		}
+	}
+}
+
+func TestUsage(t *testing.T) {
+	var buffer = &bytes.Buffer{}
+	usage(buffer)
+
+	for _, name := range commandNames {
+		if _, ok := commands[name]; !ok {
+			t.Errorf("Expected command %s to exist", name)
+		}
+
+		if !bytes.Contains(buffer.Bytes(), []byte("  "+name+" ")) {
+			t.Errorf("Expected usage to list %s, got %q", name, buffer.String())
+		}
+	}
+}
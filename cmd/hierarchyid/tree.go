@@ -0,0 +1,169 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/tentone/hierarchyid"
+	"gorm.io/driver/sqlserver"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/logger"
+)
+
+// Node of the tree read from the database.
+type treeNode struct {
+	Path     hierarchyid.HierarchyId `json:"path"`
+	Label    string                  `json:"label"`
+	Children []*treeNode             `json:"children,omitempty"`
+}
+
+// Print a hierarchy table as a tree.
+func tree(w io.Writer, args []string) error {
+	var flags = flag.NewFlagSet("tree", flag.ContinueOnError)
+	var dsn = flags.String("dsn", "", "SQL Server connection string")
+	var table = flags.String("table", "", "table to read")
+	var column = flags.String("column", "path", "hierarchyid column")
+	var label = flags.String("label", "name", "column used as label")
+	var root = flags.String("root", "/", "only print the subtree of this node")
+	var depth = flags.Int("depth", -1, "maximum depth below the root (-1 for no limit)")
+	var format = flags.String("format", "box", "output format (ascii, box, json, dot or mermaid)")
+
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	if *dsn == "" || *table == "" {
+		return errors.New("-dsn and -table are required")
+	}
+
+	rootId, err := parse(*root)
+	if err != nil {
+		return err
+	}
+
+	db, err := gorm.Open(sqlserver.Open(*dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		return err
+	}
+
+	nodes, err := readTree(db, *table, *column, *label, rootId, *depth)
+	if err != nil {
+		return err
+	}
+
+	switch *format {
+	case "ascii":
+		writeText(w, nodes, "", "|-- ", "`-- ", "|   ", "    ")
+	case "box":
+		writeText(w, nodes, "", "├── ", "└── ", "│   ", "    ")
+	case "json":
+		var encoder = json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(nodes)
+	case "dot":
+		return hierarchyid.WriteDOT(w, flattenTree(nodes, nil), hierarchyid.GraphOptions{})
+	case "mermaid":
+		return hierarchyid.WriteMermaid(w, flattenTree(nodes, nil), hierarchyid.GraphOptions{})
+	default:
+		return fmt.Errorf("unknown format %q", *format)
+	}
+
+	return nil
+}
+
+// Read the nodes of the subtree of root ordered by path and arrange them as a tree (see buildTree).
+func readTree(db *gorm.DB, table string, column string, label string, root hierarchyid.HierarchyId, depth int) ([]*treeNode, error) {
+	var col = clause.Column{Name: column}
+
+	var query = db.Table(table).
+		Select("?, ?", col, clause.Column{Name: label}).
+		Where("?.IsDescendantOf(?) = 1", col, root).
+		Order(clause.OrderByColumn{Column: col})
+
+	if depth >= 0 {
+		query = query.Where("?.GetLevel() <= ?", col, root.GetLevel()+depth)
+	}
+
+	rows, err := query.Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var nodes = []*treeNode{}
+
+	for rows.Next() {
+		var node = &treeNode{}
+		var name sql.NullString
+
+		err = rows.Scan(&node.Path, &name)
+		if err != nil {
+			return nil, err
+		}
+		node.Label = name.String
+
+		nodes = append(nodes, node)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return buildTree(nodes), nil
+}
+
+// Arrange nodes sorted depth-first as a tree, nodes whose parent is missing are attached to their nearest ancestor present.
+func buildTree(nodes []*treeNode) []*treeNode {
+	var roots = []*treeNode{}
+	var stack = []*treeNode{}
+
+	for _, node := range nodes {
+		// Rows are sorted depth-first, the parent is the last node in the stack that is an ancestor.
+		for len(stack) > 0 && !node.Path.IsDescendantOf(stack[len(stack)-1].Path) {
+			stack = stack[:len(stack)-1]
+		}
+
+		if len(stack) == 0 {
+			roots = append(roots, node)
+		} else {
+			var parent = stack[len(stack)-1]
+			parent.Children = append(parent.Children, node)
+		}
+
+		stack = append(stack, node)
+	}
+
+	return roots
+}
+
+// Write the tree indented with the provided branch drawing strings.
+func writeText(w io.Writer, nodes []*treeNode, prefix string, branch string, last string, pipe string, space string) {
+	for i, node := range nodes {
+		var isLast = i == len(nodes)-1
+
+		var connector, indent = branch, pipe
+		if isLast {
+			connector, indent = last, space
+		}
+
+		fmt.Fprintf(w, "%s%s%s %s\n", prefix, connector, node.Label, node.Path)
+		writeText(w, node.Children, prefix+indent, branch, last, pipe, space)
+	}
+}
+
+// List all nodes of the tree depth-first.
+func flattenTree(nodes []*treeNode, list []hierarchyid.Labeled) []hierarchyid.Labeled {
+	for _, node := range nodes {
+		list = append(list, hierarchyid.Labeled{Id: node.Path, Label: node.Label})
+		list = flattenTree(node.Children, list)
+	}
+
+	return list
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/tentone/hierarchyid"
+)
+
+// Create nodes from paths and labels.
+func testNodes(t *testing.T, entries ...string) []*treeNode {
+	var nodes = []*treeNode{}
+
+	for i := 0; i < len(entries); i += 2 {
+		var node = &treeNode{Label: entries[i+1]}
+
+		err := node.Path.FromString(entries[i])
+		if err != nil {
+			t.Fatal("Invalid path", entries[i], err)
+		}
+
+		nodes = append(nodes, node)
+	}
+
+	return nodes
+}
+
+type TestBuildTreeStruct struct {
+	// Paths and labels of the nodes sorted depth-first
+	nodes []string
+
+	// Expected box drawing output
+	output string
+}
+
+var TestBuildTreeData = []TestBuildTreeStruct{
+	{
+		nodes:  []string{},
+		output: "",
+	},
+	{
+		nodes:  []string{"/1/", "a", "/1/1/", "b", "/1/1/1/", "c", "/1/2/", "d", "/2/", "e"},
+		output: "├── a /1/\n│   ├── b /1/1/\n│   │   └── c /1/1/1/\n│   └── d /1/2/\n└── e /2/\n",
+	},
+	{
+		// The parent /1/1/ is missing, /1/1/1/ is attached to /1/
+		nodes:  []string{"/1/", "a", "/1/1/1/", "c", "/1/2/", "d"},
+		output: "└── a /1/\n    ├── c /1/1/1/\n    └── d /1/2/\n",
+	},
+	{
+		// No ancestor is present, the nodes are roots
+		nodes:  []string{"/1/1/", "b", "/1/2/1/", "c", "/2/", "e"},
+		output: "├── b /1/1/\n├── c /1/2/1/\n└── e /2/\n",
+	},
+	{
+		nodes:  []string{"/", "root", "/1/", "a", "/2/", "b"},
+		output: "└── root /\n    ├── a /1/\n    └── b /2/\n",
+	},
+}
+
+func TestBuildTree(t *testing.T) {
+	for _, d := range TestBuildTreeData {
+		var buffer = &bytes.Buffer{}
+		writeText(buffer, buildTree(testNodes(t, d.nodes...)), "", "├── ", "└── ", "│   ", "    ")
+
+		if buffer.String() != d.output {
+			t.Errorf("Expected %v to print\n%s\ngot\n%s", d.nodes, d.output, buffer.String())
+		}
+	}
+}
+
+func TestWriteTextASCII(t *testing.T) {
+	var buffer = &bytes.Buffer{}
+	var nodes = buildTree(testNodes(t, "/1/", "a", "/1/1/", "b", "/2/", "c"))
+
+	writeText(buffer, nodes, "", "|-- ", "`-- ", "|   ", "    ")
+
+	var expected = "|-- a /1/\n|   `-- b /1/1/\n`-- c /2/\n"
+	if buffer.String() != expected {
+		t.Errorf("Expected\n%s\ngot\n%s", expected, buffer.String())
+	}
+}
+
+func TestFlattenTree(t *testing.T) {
+	var nodes = buildTree(testNodes(t, "/1/", "a", "/1/1/", "b", "/2/", "c"))
+
+	var list = flattenTree(nodes, nil)
+	var expected = []hierarchyid.Labeled{
+		{Id: hierarchyid.HierarchyId{Data: []int64{1}}, Label: "a"},
+		{Id: hierarchyid.HierarchyId{Data: []int64{1, 1}}, Label: "b"},
+		{Id: hierarchyid.HierarchyId{Data: []int64{2}}, Label: "c"},
+	}
+
+	if len(list) != len(expected) {
+		t.Fatal("Expected", expected, "got", list)
+	}
+
+	for i := range list {
+		if list[i].Label != expected[i].Label || list[i].Id.String() != expected[i].Id.String() {
+			t.Errorf("Expected %v at %d, got %v", expected[i], i, list[i])
+		}
+	}
+}
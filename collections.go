@@ -0,0 +1,261 @@
+package hierarchyid
+
+import (
+	"sort"
+)
+
+// Entry of an ordered map.
+type orderedEntry struct {
+	key Key
+	id  HierarchyId
+}
+
+// OrderedMap is a map of hierarchyids to values of type V that iterates in depth-first order.
+//
+// Entries are stored by Key and kept sorted, the descendants of a hierarchyid are always placed right after it. The zero value is an empty map ready to use.
+type OrderedMap[V any] struct {
+	// Entries sorted by key (depth-first order).
+	entries []orderedEntry
+
+	// Values stored for each key.
+	values map[Key]V
+}
+
+// Find the position of a key in the entries (or where it should be inserted).
+func (m *OrderedMap[V]) search(key Key) (int, bool) {
+	var i = sort.Search(len(m.entries), func(i int) bool {
+		return m.entries[i].key.Compare(key) >= 0
+	})
+
+	return i, i < len(m.entries) && m.entries[i].key == key
+}
+
+// Set the value of a hierarchyid, replaces the value if already present.
+//
+// Fails if a level is out of the range supported by the binary format.
+func (m *OrderedMap[V]) Set(h HierarchyId, value V) error {
+	var key, err = h.Key()
+	if err != nil {
+		return err
+	}
+
+	if m.values == nil {
+		m.values = map[Key]V{}
+	}
+
+	var i, found = m.search(key)
+	if !found {
+		m.entries = append(m.entries, orderedEntry{})
+		copy(m.entries[i+1:], m.entries[i:])
+		m.entries[i] = orderedEntry{key: key, id: HierarchyId{Data: copyData(h.Data)}}
+	}
+
+	m.values[key] = value
+	return nil
+}
+
+// Get the value of a hierarchyid.
+func (m *OrderedMap[V]) Get(h HierarchyId) (V, bool) {
+	var key, err = h.Key()
+	if err != nil {
+		var zero V
+		return zero, false
+	}
+
+	var value, ok = m.values[key]
+	return value, ok
+}
+
+// Check if a hierarchyid is present in the map.
+func (m *OrderedMap[V]) Contains(h HierarchyId) bool {
+	var _, ok = m.Get(h)
+	return ok
+}
+
+// Delete a hierarchyid from the map, returns false if it was not present.
+func (m *OrderedMap[V]) Delete(h HierarchyId) bool {
+	var key, err = h.Key()
+	if err != nil {
+		return false
+	}
+
+	var i, found = m.search(key)
+	if !found {
+		return false
+	}
+
+	m.entries = append(m.entries[:i], m.entries[i+1:]...)
+	delete(m.values, key)
+	return true
+}
+
+// Get the number of entries in the map.
+func (m *OrderedMap[V]) Len() int {
+	return len(m.entries)
+}
+
+// All iterates over the entries of the map in depth-first order.
+//
+// The iterator is compatible with range-over-func (iter.Seq2).
+func (m *OrderedMap[V]) All() func(yield func(HierarchyId, V) bool) {
+	return func(yield func(HierarchyId, V) bool) {
+		for _, e := range m.entries {
+			if !yield(HierarchyId{Data: copyData(e.id.Data)}, m.values[e.key]) {
+				return
+			}
+		}
+	}
+}
+
+// Get the hierarchyids of the map in depth-first order.
+func (m *OrderedMap[V]) Keys() []HierarchyId {
+	var ids = make([]HierarchyId, len(m.entries))
+	for i, e := range m.entries {
+		ids[i] = HierarchyId{Data: copyData(e.id.Data)}
+	}
+
+	return ids
+}
+
+// Remove all entries that are descendants of a hierarchyid (the hierarchyid itself is kept).
+//
+// Returns the number of entries removed.
+func (m *OrderedMap[V]) RemoveDescendantsOf(h HierarchyId) int {
+	var key, err = h.Key()
+	if err != nil {
+		return 0
+	}
+
+	// Descendants are placed right after the hierarchyid in depth-first order
+	var start, found = m.search(key)
+	if found {
+		start++
+	}
+
+	var end = start
+	for end < len(m.entries) && m.entries[end].id.IsDescendantOf(h) {
+		delete(m.values, m.entries[end].key)
+		end++
+	}
+
+	m.entries = append(m.entries[:start], m.entries[end:]...)
+	return end - start
+}
+
+// Create a new map without the entries already covered by an ancestor present in the map.
+func (m *OrderedMap[V]) MinimalCover() *OrderedMap[V] {
+	var cover = &OrderedMap[V]{values: map[Key]V{}}
+
+	for _, e := range m.entries {
+		// Entries are sorted depth-first, if covered the covering ancestor is the last entry kept
+		if len(cover.entries) > 0 && e.id.IsDescendantOf(cover.entries[len(cover.entries)-1].id) {
+			continue
+		}
+
+		cover.entries = append(cover.entries, e)
+		cover.values[e.key] = m.values[e.key]
+	}
+
+	return cover
+}
+
+// Create a new map with all the entries and all of their ancestors (except for the root).
+//
+// The value function is called to get the value of ancestors not present in the map.
+func (m *OrderedMap[V]) ExpandToAncestors(value func(h HierarchyId) V) *OrderedMap[V] {
+	var expanded = &OrderedMap[V]{}
+
+	for _, e := range m.entries {
+		for _, a := range e.id.GetAncestors() {
+			if expanded.Contains(a) {
+				continue
+			}
+
+			var v, ok = m.Get(a)
+			if !ok {
+				v = value(a)
+			}
+			_ = expanded.Set(a, v)
+		}
+
+		_ = expanded.Set(e.id, m.values[e.key])
+	}
+
+	return expanded
+}
+
+// Set is a set of hierarchyids that iterates in depth-first order.
+//
+// The zero value is an empty set ready to use.
+type Set struct {
+	m OrderedMap[struct{}]
+}
+
+// Create a new set with the hierarchyids provided.
+func NewSet(ids ...HierarchyId) (*Set, error) {
+	var s = &Set{}
+
+	for _, h := range ids {
+		err := s.Add(h)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// Add a hierarchyid to the set.
+//
+// Fails if a level is out of the range supported by the binary format.
+func (s *Set) Add(h HierarchyId) error {
+	return s.m.Set(h, struct{}{})
+}
+
+// Check if a hierarchyid is present in the set.
+func (s *Set) Contains(h HierarchyId) bool {
+	return s.m.Contains(h)
+}
+
+// Remove a hierarchyid from the set, returns false if it was not present.
+func (s *Set) Remove(h HierarchyId) bool {
+	return s.m.Delete(h)
+}
+
+// Get the number of hierarchyids in the set.
+func (s *Set) Len() int {
+	return s.m.Len()
+}
+
+// All iterates over the hierarchyids of the set in depth-first order.
+//
+// The iterator is compatible with range-over-func (iter.Seq).
+func (s *Set) All() func(yield func(HierarchyId) bool) {
+	return func(yield func(HierarchyId) bool) {
+		s.m.All()(func(h HierarchyId, _ struct{}) bool {
+			return yield(h)
+		})
+	}
+}
+
+// Get the hierarchyids of the set in depth-first order.
+func (s *Set) Values() []HierarchyId {
+	return s.m.Keys()
+}
+
+// Remove all hierarchyids that are descendants of a hierarchyid (the hierarchyid itself is kept).
+//
+// Returns the number of hierarchyids removed.
+func (s *Set) RemoveDescendantsOf(h HierarchyId) int {
+	return s.m.RemoveDescendantsOf(h)
+}
+
+// Create a new set without the hierarchyids already covered by an ancestor present in the set.
+func (s *Set) MinimalCover() *Set {
+	return &Set{m: *s.m.MinimalCover()}
+}
+
+// Create a new set with all the hierarchyids and all of their ancestors (except for the root).
+func (s *Set) ExpandToAncestors() *Set {
+	return &Set{m: *s.m.ExpandToAncestors(func(HierarchyId) struct{} { return struct{}{} })}
+}
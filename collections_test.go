@@ -0,0 +1,139 @@
+package hierarchyid
+
+import (
+	"testing"
+)
+
+// Create a set from a list of paths.
+func testSet(t *testing.T, paths ...string) *Set {
+	var s = &Set{}
+	for _, p := range paths {
+		data, err := FromString(p)
+		if err != nil {
+			t.Fatal("Invalid path", p, err)
+		}
+
+		err = s.Add(HierarchyId{Data: data})
+		if err != nil {
+			t.Fatal("Error adding", p, err)
+		}
+	}
+
+	return s
+}
+
+// Get the paths of a set in iteration order.
+func setPaths(s *Set) string {
+	var r = ""
+	s.All()(func(h HierarchyId) bool {
+		r += ToString(h.Data) + " "
+		return true
+	})
+	return r
+}
+
+func TestSet(t *testing.T) {
+	var s = testSet(t, "/2/", "/1/2/", "/1/", "/1/-1/", "/1/2/", "/1/1/3/")
+
+	if s.Len() != 5 {
+		t.Errorf("Expected 5 entries, got %v", s.Len())
+	}
+
+	var expected = "/1/ /1/-1/ /1/1/3/ /1/2/ /2/ "
+	if setPaths(s) != expected {
+		t.Errorf("Expected %v, got %v", expected, setPaths(s))
+	}
+
+	if !s.Contains(HierarchyId{Data: []int64{1, 2}}) || s.Contains(HierarchyId{Data: []int64{1, 1}}) {
+		t.Error("Unexpected contains result")
+	}
+
+	if !s.Remove(HierarchyId{Data: []int64{2}}) || s.Remove(HierarchyId{Data: []int64{2}}) {
+		t.Error("Unexpected remove result")
+	}
+
+	if s.Len() != 4 {
+		t.Errorf("Expected 4 entries, got %v", s.Len())
+	}
+}
+
+func TestSetRemoveDescendantsOf(t *testing.T) {
+	var s = testSet(t, "/1/", "/1/1/", "/1/1/3/", "/1/2/", "/2/", "/2/1/")
+
+	var removed = s.RemoveDescendantsOf(HierarchyId{Data: []int64{1}})
+	if removed != 3 {
+		t.Errorf("Expected 3 removed, got %v", removed)
+	}
+
+	var expected = "/1/ /2/ /2/1/ "
+	if setPaths(s) != expected {
+		t.Errorf("Expected %v, got %v", expected, setPaths(s))
+	}
+
+	removed = s.RemoveDescendantsOf(HierarchyId{Data: []int64{3}})
+	if removed != 0 {
+		t.Errorf("Expected 0 removed, got %v", removed)
+	}
+}
+
+func TestSetMinimalCover(t *testing.T) {
+	var s = testSet(t, "/1/1/", "/1/1/3/", "/1/2/", "/1/2/4/5/", "/2/1/", "/2/1/1/", "/2/2/")
+
+	var cover = s.MinimalCover()
+	var expected = "/1/1/ /1/2/ /2/1/ /2/2/ "
+	if setPaths(cover) != expected {
+		t.Errorf("Expected %v, got %v", expected, setPaths(cover))
+	}
+
+	if s.Len() != 7 {
+		t.Error("Expected original set to be unchanged")
+	}
+}
+
+func TestSetExpandToAncestors(t *testing.T) {
+	var s = testSet(t, "/1/2/3/", "/1/4/", "/2/")
+
+	var expanded = s.ExpandToAncestors()
+	var expected = "/1/ /1/2/ /1/2/3/ /1/4/ /2/ "
+	if setPaths(expanded) != expected {
+		t.Errorf("Expected %v, got %v", expected, setPaths(expanded))
+	}
+}
+
+func TestOrderedMap(t *testing.T) {
+	var m = OrderedMap[string]{}
+	_ = m.Set(HierarchyId{Data: []int64{1, 2}}, "b")
+	_ = m.Set(HierarchyId{Data: []int64{1}}, "a")
+	_ = m.Set(HierarchyId{Data: []int64{1, 2}}, "c")
+
+	value, ok := m.Get(HierarchyId{Data: []int64{1, 2}})
+	if !ok || value != "c" || m.Len() != 2 {
+		t.Errorf("Expected c, got %v %v", value, ok)
+	}
+
+	var order = ""
+	m.All()(func(h HierarchyId, v string) bool {
+		order += ToString(h.Data) + v + " "
+		return true
+	})
+	if order != "/1/a /1/2/c " {
+		t.Errorf("Unexpected order %v", order)
+	}
+
+	var cover = m.MinimalCover()
+	if cover.Len() != 1 || !cover.Contains(HierarchyId{Data: []int64{1}}) {
+		t.Errorf("Expected cover to only contain /1/, got %v", cover.Keys())
+	}
+
+	var m2 = OrderedMap[string]{}
+	_ = m2.Set(HierarchyId{Data: []int64{1, 2, 3}}, "x")
+	var expanded = m2.ExpandToAncestors(func(h HierarchyId) string { return "filled" + ToString(h.Data) })
+	value, _ = expanded.Get(HierarchyId{Data: []int64{1}})
+	if expanded.Len() != 3 || value != "filled/1/" {
+		t.Errorf("Unexpected expanded map %v %v", expanded.Keys(), value)
+	}
+
+	if m.Set(HierarchyId{Data: []int64{281479271683152}}, "x") == nil {
+		t.Error("Expected error for out of range level")
+	}
+}
@@ -0,0 +1,276 @@
+package hierarchyid
+
+import (
+	"context"
+	"reflect"
+	"slices"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// Row of a node with the number of children and descendants of the node.
+type NodeCounts[T any] struct {
+	// Row of the node.
+	Row T `gorm:"embedded"`
+
+	// Number of direct children of the node.
+	ChildCount int64 `gorm:"column:hierarchyid_child_count"`
+
+	// Number of descendants of the node (the node itself is not included).
+	DescendantCount int64 `gorm:"column:hierarchyid_descendant_count"`
+}
+
+// Check if the node has children.
+func (n NodeCounts[T]) HasChildren() bool {
+	return n.ChildCount > 0
+}
+
+// Get the children of a node with the number of children and descendants of each child, in a single query.
+//
+// Used by tree views that load children when a node is expanded. Soft deleted rows are not counted. Results are sorted by the hierarchyid.
+func ChildrenWithCounts[T any](ctx context.Context, db *gorm.DB, model *T, parent HierarchyId) ([]NodeCounts[T], error) {
+	var t, err = newTree(ctx, db, model)
+	if err != nil {
+		return nil, err
+	}
+
+	var table = clause.Table{Name: t.schema.Table}
+	var node = clause.Column{Table: t.schema.Table, Name: t.path.DBName}
+	var child = clause.Column{Table: "child", Name: t.path.DBName}
+	var descendant = clause.Column{Table: "descendant", Name: t.path.DBName}
+
+	var children = t.db.Session(&gorm.Session{NewDB: true}).
+		Table("? AS child", table).
+		Select("COUNT(*)").
+		Where("?.GetAncestor(1) = ?", child, node)
+
+	var descendants = t.db.Session(&gorm.Session{NewDB: true}).
+		Table("? AS descendant", table).
+		Select("COUNT(*)").
+		Where("?.IsDescendantOf(?) = 1 AND ? <> ?", descendant, node, descendant, node)
+
+	children = t.inTree(children, "child")
+	descendants = t.inTree(descendants, "descendant")
+
+	if deletedAt := t.deletedAt(); deletedAt != nil {
+		children = children.Where("? IS NULL", clause.Column{Table: "child", Name: deletedAt.DBName})
+		descendants = descendants.Where("? IS NULL", clause.Column{Table: "descendant", Name: deletedAt.DBName})
+	}
+
+	var rows = []NodeCounts[T]{}
+
+	err = t.inTree(t.db.Table(t.schema.Table), t.schema.Table).
+		Select("?.*, (?) AS hierarchyid_child_count, (?) AS hierarchyid_descendant_count", table, children, descendants).
+		Where("?.GetAncestor(1) = CAST(? AS hierarchyid)", node, parent).
+		Order(clause.OrderByColumn{Column: node}).
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// Plugin that fills the fields tagged with `hierarchyid:"child_count"`, `hierarchyid:"descendant_count"` or `hierarchyid:"has_children"` after rows are found.
+//
+// Tagged fields should be ignored by gorm (e.g. `gorm:"-"`). The counts of all rows found are read with one additional query for each BatchSize rows, for models with a tree field the rows of each tree are counted separately using the tree id of the rows.
+//
+//	db.Use(hierarchyid.CountPlugin{})
+type CountPlugin struct{}
+
+// Name implements the gorm.Plugin interface.
+func (CountPlugin) Name() string {
+	return "hierarchyid:counts"
+}
+
+// Initialize implements the gorm.Plugin interface, registers the callback after the query callbacks.
+func (CountPlugin) Initialize(db *gorm.DB) error {
+	return db.Callback().Query().After("gorm:after_query").Register("hierarchyid:counts", fillCounts)
+}
+
+// Number of children and descendants of a node.
+type nodeCount struct {
+	children    int64
+	descendants int64
+}
+
+// Nodes of the rows found that belong to the same tree and their counts.
+type countGroup struct {
+	treeId any
+	nodes  []HierarchyId
+	counts map[Key]nodeCount
+}
+
+// Fill the count fields of the rows found by a query.
+func fillCounts(db *gorm.DB) {
+	if db.Error != nil || db.DryRun || db.Statement.Schema == nil {
+		return
+	}
+
+	var fields = map[string]*schema.Field{}
+	for _, field := range db.Statement.Schema.Fields {
+		switch tag := field.Tag.Get("hierarchyid"); tag {
+		case "child_count", "descendant_count", "has_children":
+			fields[tag] = field
+		}
+	}
+
+	var path = pathField(db.Statement.Schema)
+	if len(fields) == 0 || path == nil {
+		return
+	}
+
+	var ctx = db.Statement.Context
+	var values = []reflect.Value{}
+
+	// Only rows of the model are filled (e.g. Pluck and Count find other values)
+	var add = func(v reflect.Value) {
+		if v.IsValid() && v.Type() == db.Statement.Schema.ModelType {
+			values = append(values, v)
+		}
+	}
+
+	switch db.Statement.ReflectValue.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < db.Statement.ReflectValue.Len(); i++ {
+			add(reflect.Indirect(db.Statement.ReflectValue.Index(i)))
+		}
+	case reflect.Struct:
+		add(db.Statement.ReflectValue)
+	}
+
+	var t, err = baseTree(ctx, db.Session(&gorm.Session{NewDB: true}), db.Statement.Model, db.Statement.Schema)
+	if err != nil {
+		_ = db.AddError(err)
+		return
+	}
+
+	// Rows are grouped by their own tree id (if the model has a tree field), the counts of each tree are read separately
+	var groups = []*countGroup{}
+	var rows = make([]*countGroup, len(values))
+	var keys = make([]Key, len(values))
+
+	for i, v := range values {
+		var value, _ = path.ValueOf(ctx, v)
+		var h, ok = value.(HierarchyId)
+		if !ok || h.Data == nil {
+			continue
+		}
+
+		var id any
+		if t.treeField != nil {
+			id, _ = t.treeField.ValueOf(ctx, v)
+		}
+
+		var j = slices.IndexFunc(groups, func(g *countGroup) bool {
+			return reflect.DeepEqual(g.treeId, id)
+		})
+		if j < 0 {
+			j = len(groups)
+			groups = append(groups, &countGroup{treeId: id})
+		}
+
+		groups[j].nodes = append(groups[j].nodes, h)
+		rows[i] = groups[j]
+		keys[i], _ = h.Key()
+	}
+
+	if len(groups) == 0 {
+		return
+	}
+
+	for _, g := range groups {
+		var gt = *t
+		gt.treeId = g.treeId
+
+		g.counts, err = gt.counts(g.nodes)
+		if err != nil {
+			_ = db.AddError(err)
+			return
+		}
+	}
+
+	for i, v := range values {
+		var count nodeCount
+		if rows[i] != nil {
+			count = rows[i].counts[keys[i]]
+		}
+
+		var set = map[string]any{
+			"child_count":      count.children,
+			"descendant_count": count.descendants,
+			"has_children":     count.children > 0,
+		}
+
+		for tag, field := range fields {
+			err := field.Set(ctx, v, set[tag])
+			if err != nil {
+				_ = db.AddError(err)
+				return
+			}
+		}
+	}
+}
+
+// Get the number of children and descendants of each node, soft deleted rows are not counted.
+func (t *tree) counts(nodes []HierarchyId) (map[Key]nodeCount, error) {
+	var table = clause.Table{Name: t.schema.Table}
+	var node = clause.Column{Table: "node", Name: t.path.DBName}
+	var descendant = clause.Column{Table: "descendant", Name: t.path.DBName}
+
+	var join = "LEFT JOIN ? AS descendant ON ?.IsDescendantOf(?) = 1 AND ? <> ?"
+	var vars = []any{table, descendant, node, descendant, node}
+
+	if deletedAt := t.deletedAt(); deletedAt != nil {
+		join += " AND ? IS NULL"
+		vars = append(vars, clause.Column{Table: "descendant", Name: deletedAt.DBName})
+	}
+
+	if t.treeField != nil {
+		join += " AND ? = ?"
+		vars = append(vars, clause.Column{Table: "descendant", Name: t.treeField.DBName}, t.treeId)
+	}
+
+	var counts = map[Key]nodeCount{}
+
+	for start := 0; start < len(nodes); start += BatchSize {
+		var end = start + BatchSize
+		if end > len(nodes) {
+			end = len(nodes)
+		}
+
+		rows, err := t.inTree(t.db.Table("? AS node", table), "node").
+			Select("?, SUM(CASE WHEN ?.GetAncestor(1) = ? THEN 1 ELSE 0 END), COUNT(?)", node, descendant, node, descendant).
+			Joins(join, vars...).
+			Where("? IN ?", node, nodes[start:end]).
+			Clauses(clause.GroupBy{Columns: []clause.Column{node}}).
+			Rows()
+		if err != nil {
+			return nil, err
+		}
+
+		for rows.Next() {
+			var h HierarchyId
+			var count nodeCount
+
+			err = rows.Scan(&h, &count.children, &count.descendants)
+			if err != nil {
+				rows.Close()
+				return nil, err
+			}
+
+			var key, _ = h.Key()
+			counts[key] = count
+		}
+
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return counts, nil
+}
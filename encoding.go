@@ -0,0 +1,100 @@
+package hierarchyid
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// String implements the fmt.Stringer interface, returns the path representation (e.g. '/1/2/').
+func (j HierarchyId) String() string {
+	return ToString(j.Data)
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+//
+// The text representation is the path of the hierarchyid (e.g. '/1/2/'), allows the type to be used in YAML, TOML, XML and env configs. Use Key as JSON map key.
+func (j HierarchyId) MarshalText() ([]byte, error) {
+	return []byte(ToString(j.Data)), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (j *HierarchyId) UnmarshalText(text []byte) error {
+	var data, err = FromString(string(text))
+	if err != nil {
+		return err
+	}
+
+	j.Data = data
+	return nil
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface using the SQL Server binary format.
+func (j HierarchyId) MarshalBinary() ([]byte, error) {
+	return Encode(j.Data)
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface using the SQL Server binary format.
+func (j *HierarchyId) UnmarshalBinary(data []byte) error {
+	var levels, err = Decode(data)
+	if err != nil {
+		return err
+	}
+
+	j.Data = levels
+	return nil
+}
+
+// GobEncode implements the gob.GobEncoder interface using the SQL Server binary format.
+func (j HierarchyId) GobEncode() ([]byte, error) {
+	return j.MarshalBinary()
+}
+
+// GobDecode implements the gob.GobDecoder interface using the SQL Server binary format.
+func (j *HierarchyId) GobDecode(data []byte) error {
+	return j.UnmarshalBinary(data)
+}
+
+// LogValue implements the slog.LogValuer interface, hierarchyids are logged using their path.
+func (j HierarchyId) LogValue() slog.Value {
+	return slog.StringValue(ToString(j.Data))
+}
+
+// Format implements the fmt.Formatter interface.
+//
+// Supported verbs:
+//   - %s and %v print the path (e.g. '/1/2/'), %q prints the path quoted.
+//   - %x prints the SQL Server hex literal in lowercase (e.g. '0x5ac0'), %X in uppercase (e.g. '0x5AC0').
+//   - %b prints the bit layout of the encoded value (e.g. '0101101011').
+//   - %#v prints the Go syntax representation.
+func (j HierarchyId) Format(f fmt.State, verb rune) {
+	switch verb {
+	case 's', 'v':
+		if verb == 'v' && f.Flag('#') {
+			fmt.Fprintf(f, "hierarchyid.HierarchyId{Data:%#v}", j.Data)
+			return
+		}
+		fmt.Fprint(f, ToString(j.Data))
+	case 'q':
+		fmt.Fprintf(f, "%q", ToString(j.Data))
+	case 'x', 'X':
+		var str, err = ToHex(j.Data)
+		if err != nil {
+			fmt.Fprintf(f, "%%!%c(hierarchyid=%v)", verb, err)
+			return
+		}
+		if verb == 'x' {
+			str = strings.ToLower(str)
+		}
+		fmt.Fprint(f, str)
+	case 'b':
+		var data, err = Encode(j.Data)
+		if err != nil {
+			fmt.Fprintf(f, "%%!%c(hierarchyid=%v)", verb, err)
+			return
+		}
+		fmt.Fprint(f, binaryString(data))
+	default:
+		fmt.Fprintf(f, "%%!%c(hierarchyid=%s)", verb, ToString(j.Data))
+	}
+}
@@ -0,0 +1,95 @@
+package hierarchyid
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/xml"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+type TestFormatStruct struct {
+	format string
+	input  []int64
+	output string
+}
+
+var TestFormatData []TestFormatStruct = []TestFormatStruct{
+	{"%s", []int64{}, "/"},
+	{"%s", []int64{1, 2, 754}, "/1/2/754/"},
+	{"%v", []int64{1, -1, 4}, "/1/-1/4/"},
+	{"%q", []int64{1, 1}, "\"/1/1/\""},
+	{"%x", []int64{}, "0x"},
+	{"%x", []int64{1, 1}, "0x5ac0"},
+	{"%X", []int64{1, 1}, "0x5AC0"},
+	{"%X", []int64{1, 2, 754}, "0x5B7A9150"},
+	{"%b", []int64{0}, "01001"},
+	{"%b", []int64{5200}, "1111100000000000000000000000000000000010001"},
+	{"%#v", []int64{1, 2}, "hierarchyid.HierarchyId{Data:[]int64{1, 2}}"},
+}
+
+func TestFormat(t *testing.T) {
+	for _, d := range TestFormatData {
+		var result = fmt.Sprintf(d.format, HierarchyId{Data: d.input})
+
+		if result != d.output {
+			t.Errorf("Expected %v with %v to return %v, got %v", d.input, d.format, d.output, result)
+		}
+	}
+}
+
+func TestMarshalText(t *testing.T) {
+	type TestMarshalTextXml struct {
+		Path HierarchyId `xml:"path"`
+	}
+
+	data, err := xml.Marshal(TestMarshalTextXml{Path: HierarchyId{Data: []int64{1, 2}}})
+	if err != nil {
+		t.Fatal("Error marshaling xml", err)
+	}
+
+	if string(data) != "<TestMarshalTextXml><path>/1/2/</path></TestMarshalTextXml>" {
+		t.Errorf("Unexpected xml %v", string(data))
+	}
+
+	var h = HierarchyId{}
+	err = h.UnmarshalText([]byte("/3/1/"))
+	if err != nil || !slices.Equal(h.Data, []int64{3, 1}) {
+		t.Errorf("Expected /3/1/ got %v %v", h.Data, err)
+	}
+}
+
+func TestMarshalBinary(t *testing.T) {
+	for _, d := range TestEncodeDecodeData {
+		if d.output == nil {
+			continue
+		}
+
+		data, err := HierarchyId{Data: d.output}.MarshalBinary()
+		if err != nil {
+			t.Errorf("Error marshaling %v: %v", d.output, err)
+		}
+
+		var h = HierarchyId{}
+		err = h.UnmarshalBinary(data)
+		if err != nil || !slices.Equal(h.Data, d.output) {
+			t.Errorf("Expected %v got %v %v", d.output, h.Data, err)
+		}
+	}
+}
+
+func TestGob(t *testing.T) {
+	var buf bytes.Buffer
+
+	err := gob.NewEncoder(&buf).Encode(HierarchyId{Data: []int64{1, 2, 754}})
+	if err != nil {
+		t.Fatal("Error encoding gob", err)
+	}
+
+	var h = HierarchyId{}
+	err = gob.NewDecoder(&buf).Decode(&h)
+	if err != nil || !slices.Equal(h.Data, []int64{1, 2, 754}) {
+		t.Errorf("Expected /1/2/754/ got %v %v", h.Data, err)
+	}
+}
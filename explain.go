@@ -0,0 +1,58 @@
+package hierarchyid
+
+// Describes how a single level of a hierarchyid is stored in the SQL Server binary format.
+type EncodedLevel struct {
+	// Value of the level
+	Value int64
+
+	// Position of the first bit of the level in the encoded data.
+	Start int
+
+	// Position after the last bit of the level in the encoded data.
+	End int
+
+	// Bits used to store the level (e.g. "01011").
+	Bits string
+
+	// Pattern used to encode the level.
+	Pattern *HierarchyIdPattern
+}
+
+// Explain how a hierarchyid is stored in the SQL Server binary format.
+//
+// Returns the bit span, pattern and value of each level, useful to debug values read from SQL traces.
+func Explain(data []byte) ([]EncodedLevel, error) {
+	var levels = []EncodedLevel{}
+	if len(data) == 0 {
+		return levels, nil
+	}
+
+	var bin = binaryString(data)
+	var start = 0
+
+	for start < len(bin) {
+		var pattern, err = testPatterns(bin[start:])
+		if err != nil {
+			return nil, err
+		}
+
+		var value int64
+		value, err = decodeValue(pattern.Pattern, bin[start:])
+		if err != nil {
+			return nil, err
+		}
+
+		var end = start + len(pattern.Pattern)
+		levels = append(levels, EncodedLevel{
+			Value:   value + pattern.Min,
+			Start:   start,
+			End:     end,
+			Bits:    bin[start:end],
+			Pattern: pattern,
+		})
+
+		start = end
+	}
+
+	return levels, nil
+}
@@ -0,0 +1,55 @@
+package hierarchyid
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestExplain(t *testing.T) {
+	input, _ := hex.DecodeString("5B7A9150")
+
+	levels, err := Explain(input)
+	if err != nil {
+		t.Fatal("Error explaining 0x5B7A9150", err)
+	}
+
+	var expected = []EncodedLevel{
+		{Value: 1, Start: 0, End: 5, Bits: "01011"},
+		{Value: 2, Start: 5, End: 10, Bits: "01101"},
+		{Value: 754, Start: 10, End: 28, Bits: "111010100100010101"},
+	}
+	var patterns = []string{"01xxT", "01xxT", "1110xxx0xxx0x1xxxT"}
+
+	if len(levels) != len(expected) {
+		t.Fatalf("Expected %v levels, got %v", len(expected), len(levels))
+	}
+
+	for i, level := range levels {
+		var e = expected[i]
+		if level.Value != e.Value || level.Start != e.Start || level.End != e.End || level.Bits != e.Bits || level.Pattern.Pattern != patterns[i] {
+			t.Errorf("Expected level %v to be %v %v, got %v %v", i, e, patterns[i], level, level.Pattern.Pattern)
+		}
+	}
+}
+
+func TestExplainDecode(t *testing.T) {
+	for _, d := range TestEncodeDecodeData {
+		input, _ := hex.DecodeString(d.input)
+
+		levels, err := Explain(input)
+		if err != nil {
+			t.Errorf("Error explaining %v: %v", d.input, err)
+		}
+
+		if len(levels) != len(d.output) {
+			t.Errorf("Expected 0x%v to have %v levels, got %v", d.input, len(d.output), len(levels))
+			continue
+		}
+
+		for i, level := range levels {
+			if level.Value != d.output[i] {
+				t.Errorf("Expected 0x%v level %v to be %v, got %v", d.input, i, d.output[i], level.Value)
+			}
+		}
+	}
+}
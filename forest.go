@@ -0,0 +1,114 @@
+package hierarchyid
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+
+	"gorm.io/gorm"
+)
+
+// Returned when the model has a tree field and the context has no tree id (see WithTree).
+var ErrNoTreeId = errors.New("hierarchyid: model has a tree field and the context has no tree id")
+
+// Returned when moving rows between trees with a model without a field tagged with `hierarchyid:"tree"`.
+var ErrNoTreeField = errors.New("hierarchyid: model has no tree field")
+
+// Key of the tree id stored in the context.
+type treeKey struct{}
+
+// Create a context with the tree id used by the tree helpers.
+//
+// Tables that store multiple independent trees (e.g. one per tenant) identify the tree of each row with a field tagged with `hierarchyid:"tree"`. Queries built by the tree helpers and scopes are restricted to the rows of the tree id in the context.
+func WithTree(ctx context.Context, id any) context.Context {
+	return context.WithValue(ctx, treeKey{}, id)
+}
+
+// Get the tree id stored in the context.
+func TreeFromContext(ctx context.Context) (any, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+
+	var id = ctx.Value(treeKey{})
+	return id, id != nil
+}
+
+// Move a node and all of its descendants from the tree of the context to a new child of a parent in another tree.
+//
+// The new key is the next dense child of the target parent, the tree id and the hierarchyid of the rows (including soft deleted rows) are updated in a single transaction.
+//
+// Returns the hierarchyid of the node in the target tree.
+func MoveToTree(ctx context.Context, db *gorm.DB, model any, node HierarchyId, targetTree any, targetParent HierarchyId) (HierarchyId, error) {
+	var t, err = newTree(ctx, db, model)
+	if err != nil {
+		return HierarchyId{}, err
+	}
+
+	if t.treeField == nil {
+		return HierarchyId{}, ErrNoTreeField
+	}
+
+	if node.GetLevel() == 0 {
+		return HierarchyId{}, ErrInvalidMove
+	}
+
+	same, err := t.sameTree(targetTree)
+	if err != nil {
+		return HierarchyId{}, err
+	}
+
+	if same && (slices.Equal(targetParent.Data, node.Data) || targetParent.IsDescendantOf(node)) {
+		return HierarchyId{}, ErrInvalidMove
+	}
+
+	var target = *t
+	target.treeId = targetTree
+
+	var root HierarchyId
+
+	err = t.db.Transaction(func(tx *gorm.DB) error {
+		var values, err = target.childValues(tx, targetParent)
+		if err != nil {
+			return err
+		}
+
+		value, err := DenseAllocator{}.Next(values)
+		if err != nil {
+			return err
+		}
+		root = targetParent.Child(value)
+
+		return t.query(tx).Unscoped().
+			Where("?.IsDescendantOf(CAST(? AS hierarchyid)) = 1", t.column(), node).
+			Updates(map[string]any{
+				t.path.DBName:      gorm.Expr("?.GetReparentedValue(CAST(? AS hierarchyid), CAST(? AS hierarchyid))", t.column(), node, root),
+				t.treeField.DBName: targetTree,
+			}).Error
+	})
+	if err != nil {
+		return HierarchyId{}, err
+	}
+
+	return root, nil
+}
+
+// Check if a tree id is the tree id of the tree, both ids are converted to the type of the tree field before comparing (e.g. int and uint).
+func (t *tree) sameTree(id any) (bool, error) {
+	var ctx = t.db.Statement.Context
+	var values = [2]any{}
+
+	for i, v := range []any{id, t.treeId} {
+		var row = reflect.New(t.schema.ModelType).Elem()
+
+		err := t.treeField.Set(ctx, row, v)
+		if err != nil {
+			return false, err
+		}
+
+		values[i], _ = t.treeField.ValueOf(ctx, row)
+	}
+
+	return reflect.DeepEqual(values[0], values[1]), nil
+}
@@ -0,0 +1,207 @@
+package hierarchyid
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Hierarchyid with a label, used to export graphs.
+type Labeled struct {
+	// Position of the node in the tree
+	Id HierarchyId
+
+	// Text presented in the node
+	Label string
+}
+
+// Options used to export graphs.
+type GraphOptions struct {
+	// Group the nodes of each level of the tree in a cluster (subgraph).
+	ClusterLevels bool
+
+	// Highlight a node and all of its ancestors (if present in the graph).
+	Highlight *HierarchyId
+}
+
+// Node of the graph with its parent already resolved.
+type graphNode struct {
+	Labeled
+
+	// Index of the parent in the list of nodes (-1 if the node has no parent in the graph).
+	parent int
+
+	// Node is highlighted
+	highlight bool
+}
+
+// Sort nodes in depth-first order and resolve the parent of each node.
+//
+// The parent is obtained with GetAncestor, when the direct parent is not in the list the nearest ancestor present is used.
+func graphNodes(nodes []Labeled, opts GraphOptions) []graphNode {
+	var sorted = make([]graphNode, len(nodes))
+	for i, n := range nodes {
+		sorted[i] = graphNode{Labeled: n, parent: -1}
+	}
+
+	sort.SliceStable(sorted, func(a, b int) bool {
+		return CompareDepthFirst(sorted[a].Id.Data, sorted[b].Id.Data) < 0
+	})
+
+	var index = map[string]int{}
+	for i, n := range sorted {
+		index[ToString(n.Id.Data)] = i
+	}
+
+	for i := range sorted {
+		var n = &sorted[i]
+
+		var ancestor = n.Id
+		for ancestor.GetLevel() > 0 {
+			ancestor = ancestor.GetAncestor()
+			if p, ok := index[ToString(ancestor.Data)]; ok {
+				n.parent = p
+				break
+			}
+		}
+
+		if opts.Highlight != nil {
+			n.highlight = slices.Equal(n.Id.Data, opts.Highlight.Data) || opts.Highlight.IsDescendantOf(n.Id)
+		}
+	}
+
+	return sorted
+}
+
+// Group the index of the nodes by tree level.
+func graphLevels(nodes []graphNode) ([]int, map[int][]int) {
+	var levels = []int{}
+	var byLevel = map[int][]int{}
+
+	for i, n := range nodes {
+		var level = n.Id.GetLevel()
+		if _, ok := byLevel[level]; !ok {
+			levels = append(levels, level)
+		}
+		byLevel[level] = append(byLevel[level], i)
+	}
+
+	sort.Ints(levels)
+	return levels, byLevel
+}
+
+// Write a set of hierarchyids as a Graphviz DOT graph.
+//
+// Edges connect each node to its parent, siblings are ordered in depth-first order.
+func WriteDOT(w io.Writer, nodes []Labeled, opts GraphOptions) error {
+	var b = bufio.NewWriter(w)
+	var sorted = graphNodes(nodes, opts)
+
+	var writeNode = func(indent string, i int) {
+		var n = sorted[i]
+		fmt.Fprintf(b, "%s%q [label=%q", indent, ToString(n.Id.Data), n.Label)
+		if n.highlight {
+			b.WriteString(", style=filled, fillcolor=\"#ffd966\"")
+		}
+		b.WriteString("];\n")
+	}
+
+	b.WriteString("digraph hierarchy {\n")
+	b.WriteString("  node [shape=box];\n")
+
+	if opts.ClusterLevels {
+		var levels, byLevel = graphLevels(sorted)
+		for _, level := range levels {
+			fmt.Fprintf(b, "  subgraph cluster_level_%d {\n", level)
+			fmt.Fprintf(b, "    label=\"Level %d\";\n", level)
+			for _, i := range byLevel[level] {
+				writeNode("    ", i)
+			}
+			b.WriteString("  }\n")
+		}
+	} else {
+		for i := range sorted {
+			writeNode("  ", i)
+		}
+	}
+
+	for _, n := range sorted {
+		if n.parent < 0 {
+			continue
+		}
+
+		var p = sorted[n.parent]
+		fmt.Fprintf(b, "  %q -> %q", ToString(p.Id.Data), ToString(n.Id.Data))
+		if n.highlight && p.highlight {
+			b.WriteString(" [penwidth=2, color=\"#bf9000\"]")
+		}
+		b.WriteString(";\n")
+	}
+
+	b.WriteString("}\n")
+	return b.Flush()
+}
+
+// Write a set of hierarchyids as a Mermaid flowchart.
+//
+// Edges connect each node to its parent, siblings are ordered in depth-first order.
+func WriteMermaid(w io.Writer, nodes []Labeled, opts GraphOptions) error {
+	var b = bufio.NewWriter(w)
+	var sorted = graphNodes(nodes, opts)
+
+	var writeNode = func(indent string, i int) {
+		var label = strings.ReplaceAll(sorted[i].Label, "\"", "#quot;")
+		fmt.Fprintf(b, "%sn%d[\"%s\"]\n", indent, i, label)
+	}
+
+	b.WriteString("graph TD\n")
+
+	if opts.ClusterLevels {
+		var levels, byLevel = graphLevels(sorted)
+		for _, level := range levels {
+			fmt.Fprintf(b, "  subgraph level_%d [\"Level %d\"]\n", level, level)
+			for _, i := range byLevel[level] {
+				writeNode("    ", i)
+			}
+			b.WriteString("  end\n")
+		}
+	} else {
+		for i := range sorted {
+			writeNode("  ", i)
+		}
+	}
+
+	var edge = 0
+	var highlightEdges = []string{}
+	for i, n := range sorted {
+		if n.parent < 0 {
+			continue
+		}
+
+		fmt.Fprintf(b, "  n%d --> n%d\n", n.parent, i)
+		if n.highlight && sorted[n.parent].highlight {
+			highlightEdges = append(highlightEdges, fmt.Sprint(edge))
+		}
+		edge++
+	}
+
+	var highlightNodes = []string{}
+	for i, n := range sorted {
+		if n.highlight {
+			highlightNodes = append(highlightNodes, fmt.Sprintf("n%d", i))
+		}
+	}
+
+	if len(highlightNodes) > 0 {
+		b.WriteString("  classDef highlight fill:#ffd966,stroke:#bf9000\n")
+		fmt.Fprintf(b, "  class %s highlight\n", strings.Join(highlightNodes, ","))
+	}
+	if len(highlightEdges) > 0 {
+		fmt.Fprintf(b, "  linkStyle %s stroke:#bf9000,stroke-width:2px\n", strings.Join(highlightEdges, ","))
+	}
+
+	return b.Flush()
+}
@@ -0,0 +1,90 @@
+package hierarchyid
+
+import (
+	"bytes"
+	"testing"
+)
+
+var TestGraphNodes = []Labeled{
+	{HierarchyId{Data: []int64{1, 2}}, "C"},
+	{HierarchyId{Data: []int64{1}}, "A"},
+	{HierarchyId{Data: []int64{1, 1}}, "B"},
+	{HierarchyId{Data: []int64{2, 1, 1}}, "D"},
+}
+
+func TestWriteDOT(t *testing.T) {
+	var buf bytes.Buffer
+
+	err := WriteDOT(&buf, TestGraphNodes, GraphOptions{})
+	if err != nil {
+		t.Fatal("Error writing graph", err)
+	}
+
+	var expected = `digraph hierarchy {
+  node [shape=box];
+  "/1/" [label="A"];
+  "/1/1/" [label="B"];
+  "/1/2/" [label="C"];
+  "/2/1/1/" [label="D"];
+  "/1/" -> "/1/1/";
+  "/1/" -> "/1/2/";
+}
+`
+	if buf.String() != expected {
+		t.Errorf("Expected %v, got %v", expected, buf.String())
+	}
+}
+
+func TestWriteDOTOptions(t *testing.T) {
+	var buf bytes.Buffer
+	var highlight = HierarchyId{Data: []int64{1, 2}}
+
+	err := WriteDOT(&buf, TestGraphNodes[:3], GraphOptions{ClusterLevels: true, Highlight: &highlight})
+	if err != nil {
+		t.Fatal("Error writing graph", err)
+	}
+
+	var expected = `digraph hierarchy {
+  node [shape=box];
+  subgraph cluster_level_1 {
+    label="Level 1";
+    "/1/" [label="A", style=filled, fillcolor="#ffd966"];
+  }
+  subgraph cluster_level_2 {
+    label="Level 2";
+    "/1/1/" [label="B"];
+    "/1/2/" [label="C", style=filled, fillcolor="#ffd966"];
+  }
+  "/1/" -> "/1/1/";
+  "/1/" -> "/1/2/" [penwidth=2, color="#bf9000"];
+}
+`
+	if buf.String() != expected {
+		t.Errorf("Expected %v, got %v", expected, buf.String())
+	}
+}
+
+func TestWriteMermaid(t *testing.T) {
+	var buf bytes.Buffer
+	var highlight = HierarchyId{Data: []int64{1, 2}}
+
+	err := WriteMermaid(&buf, TestGraphNodes, GraphOptions{Highlight: &highlight})
+	if err != nil {
+		t.Fatal("Error writing graph", err)
+	}
+
+	var expected = `graph TD
+  n0["A"]
+  n1["B"]
+  n2["C"]
+  n3["D"]
+  n0 --> n1
+  n0 --> n2
+  classDef highlight fill:#ffd966,stroke:#bf9000
+  class n0,n2 highlight
+  linkStyle 1 stroke:#bf9000,stroke-width:2px
+`
+	if buf.String() != expected {
+		t.Errorf("Expected %v, got %v", expected, buf.String())
+	}
+}
@@ -1,14 +1,22 @@
 package hierarchyid
 
 import (
+	"database/sql"
 	"database/sql/driver"
 	"encoding/json"
 	"errors"
+	"slices"
+	"strings"
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/schema"
 )
 
+// When enabled Scan only accepts the binary data provided by the SQL Server driver.
+//
+// By default Scan also accepts strings in text ('/1/2/') and hex ('0x5AC0') format, e.g. when reading the result of path.ToString() or a view that casts the column.
+var StrictScan = false
+
 // HierarchyId is a structure to represent database hierarchy ids.
 type HierarchyId struct {
 	// Path of the hierarchy (e.g "/1/2/3/4/")
@@ -60,15 +68,16 @@ func (j *HierarchyId) IsDescendantOf(parent HierarchyId) bool {
 // The position will be calculated based on the old and new parents.
 //
 // E.g. if the element is on position '/1/2/57/8/' old parents is '/1/2/' and new parent is '/1/3/' the new position will be '/1/3/57/8/'
+//
+// As in SQL Server the old ancestor itself is moved to the new ancestor (previous versions returned an empty hierarchyid for it). Values that are not the old ancestor or one of its descendants return an empty hierarchyid.
+//
+// The result is a new slice, it does not share memory with the hierarchyid or the ancestors.
 func (j *HierarchyId) GetReparentedValue(oldAncestor HierarchyId, newAncestor HierarchyId) HierarchyId {
-	if !j.IsDescendantOf(oldAncestor) {
+	if !slices.Equal(j.Data, oldAncestor.Data) && !j.IsDescendantOf(oldAncestor) {
 		return HierarchyId{}
 	}
 
-	path := j.Data
-	path = append(newAncestor.Data, path[len(oldAncestor.Data):]...)
-
-	return HierarchyId{Data: path}
+	return HierarchyId{Data: Join(newAncestor.Data, j.Data[len(oldAncestor.Data):])}
 }
 
 // Get all ancestors of a hierarchyid.
@@ -85,6 +94,15 @@ func (j *HierarchyId) GetAncestors() []HierarchyId {
 	return p
 }
 
+// Get the root, the ancestors and the hierarchyid itself, sorted from the root to the hierarchyid.
+func lineage(h HierarchyId) []HierarchyId {
+	if h.GetLevel() == 0 {
+		return []HierarchyId{GetRoot()}
+	}
+
+	return append(append([]HierarchyId{GetRoot()}, h.GetAncestors()...), HierarchyId{Data: copyData(h.Data)})
+}
+
 // Create a string representation of the hierarchyid data type
 func (j *HierarchyId) ToString() string {
 	return ToString(j.Data)
@@ -95,6 +113,49 @@ func (j *HierarchyId) GetAncestor() HierarchyId {
 	return HierarchyId{Data: GetAncestor(j.Data)}
 }
 
+// Get the lowest common ancestor of this and other hierarchyids.
+func (j *HierarchyId) LowestCommonAncestor(others ...HierarchyId) HierarchyId {
+	var data = []HierarchyIdData{j.Data}
+	for _, o := range others {
+		data = append(data, o.Data)
+	}
+
+	return HierarchyId{Data: LowestCommonAncestor(data...)}
+}
+
+// Get the number of edges in the tree between this and another hierarchyid.
+func (j *HierarchyId) Distance(other HierarchyId) int {
+	return Distance(j.Data, other.Data)
+}
+
+// Get the relative path from this hierarchyid to another (e.g. '../3/1/').
+func (j *HierarchyId) Relative(to HierarchyId) string {
+	return Relative(j.Data, to.Data)
+}
+
+// Resolve a relative path (e.g. '../3/1/') from this hierarchyid.
+func (j *HierarchyId) Resolve(rel string) (HierarchyId, error) {
+	var data, err = Resolve(j.Data, rel)
+	if err != nil {
+		return HierarchyId{}, err
+	}
+
+	return HierarchyId{Data: data}, nil
+}
+
+// Remove the levels of an ancestor from the start of this hierarchyid.
+//
+// Returns false if the ancestor is not an ancestor (or equal) of the hierarchyid.
+func (j *HierarchyId) StripPrefix(ancestor HierarchyId) (HierarchyId, bool) {
+	var data, ok = StripPrefix(j.Data, ancestor.Data)
+	return HierarchyId{Data: data}, ok
+}
+
+// Append the levels of a suffix to this hierarchyid.
+func (j *HierarchyId) Join(suffix HierarchyId) HierarchyId {
+	return HierarchyId{Data: Join(j.Data, suffix.Data)}
+}
+
 // When marshaling to JSON, we want the field formatted as a string.
 func (j HierarchyId) MarshalJSON() ([]byte, error) {
 	return json.Marshal(ToString(j.Data))
@@ -123,12 +184,17 @@ func (j *HierarchyId) UnmarshalJSON(data []byte) error {
 
 // Value implements the driver.Valuer interface.
 //
-// Used to provide a value to the SQL server for storage.
+// Used to provide a value to the SQL server for storage. The value is checked against DefaultLimits before being encoded.
 func (j HierarchyId) Value() (driver.Value, error) {
 	if j.Data == nil {
 		return nil, nil
 	}
 
+	err := Validate(j, DefaultLimits)
+	if err != nil {
+		return nil, err
+	}
+
 	data, err := Encode(j.Data)
 	if err != nil {
 		return nil, err
@@ -140,6 +206,8 @@ func (j HierarchyId) Value() (driver.Value, error) {
 // Scan implements the sql.Scanner interface.
 //
 // Used to read the value provided by the SQL server.
+//
+// Binary data ([]byte, *[]byte and sql.RawBytes) is decoded from the SQL Server format, strings are accepted in text ('/1/2/') and hex ('0x5AC0') representations unless StrictScan is enabled.
 func (j *HierarchyId) Scan(src any) error {
 	if src == nil {
 		j.Data = nil
@@ -148,14 +216,53 @@ func (j *HierarchyId) Scan(src any) error {
 
 	switch src := src.(type) {
 	case []byte:
-		var err error
-		j.Data, err = Decode(src)
-		if err != nil {
-			return err
+		return j.scanBytes(src)
+	case sql.RawBytes:
+		return j.scanBytes(src)
+	case *[]byte:
+		if src == nil {
+			j.Data = nil
+			return nil
+		}
+		return j.scanBytes(*src)
+	case string:
+		if StrictScan {
+			return errors.New("incompatible type to scan")
 		}
+		return j.scanString(src)
 	default:
 		return errors.New("incompatible type to scan")
 	}
+}
+
+// Decode binary data in the SQL Server format into the hierarchyid.
+func (j *HierarchyId) scanBytes(src []byte) error {
+	data, err := Decode(src)
+	if err != nil {
+		return err
+	}
+
+	j.Data = data
+	return nil
+}
+
+// Parse a string representation of the hierarchyid, detecting the format used.
+//
+// Values prefixed with '0x' are read as SQL Server hex literals, everything else as a path (e.g. '/1/2/').
+func (j *HierarchyId) scanString(src string) error {
+	var str = strings.TrimSpace(src)
+
+	var data HierarchyIdData
+	var err error
+	if len(str) >= 2 && str[0] == '0' && (str[1] == 'x' || str[1] == 'X') {
+		data, err = FromHex(str)
+	} else {
+		data, err = FromString(str)
+	}
+	if err != nil {
+		return err
+	}
 
+	j.Data = data
 	return nil
 }
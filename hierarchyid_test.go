@@ -0,0 +1,167 @@
+package hierarchyid
+
+import (
+	"database/sql"
+	"slices"
+	"testing"
+)
+
+type TestScanStruct struct {
+	input  any
+	output []int64
+}
+
+var TestScanBytes = []byte{0x5A, 0xC0}
+
+var TestScanData []TestScanStruct = []TestScanStruct{
+	{nil, nil},
+	{[]byte{}, []int64{}},
+	{[]byte{0x5A, 0xC0}, []int64{1, 1}},
+	{sql.RawBytes{0x5B, 0x7A, 0x91, 0x50}, []int64{1, 2, 754}},
+	{&TestScanBytes, []int64{1, 1}},
+	{"/", []int64{}},
+	{"/1/2/754/", []int64{1, 2, 754}},
+	{" /1/-1/4/ ", []int64{1, -1, 4}},
+	{"0x5AC0", []int64{1, 1}},
+	{"0X5ac0", []int64{1, 1}},
+	{"0x", []int64{}},
+}
+
+func TestScan(t *testing.T) {
+	for _, d := range TestScanData {
+		var h = HierarchyId{}
+
+		err := h.Scan(d.input)
+		if err != nil {
+			t.Errorf("Error scanning %v: %v", d.input, err)
+		}
+
+		if !slices.Equal(h.Data, d.output) || (h.Data == nil) != (d.output == nil) {
+			t.Errorf("Expected %v to return %v, got %v", d.input, d.output, h.Data)
+		}
+	}
+}
+
+func TestScanInvalid(t *testing.T) {
+	var inputs = []any{"/a/", "0xZZ", 10, 1.5}
+
+	for _, input := range inputs {
+		var h = HierarchyId{}
+		if h.Scan(input) == nil {
+			t.Errorf("Expected %v to fail", input)
+		}
+	}
+}
+
+func TestScanStrict(t *testing.T) {
+	StrictScan = true
+	defer func() { StrictScan = false }()
+
+	var h = HierarchyId{}
+	if h.Scan("/1/2/") == nil {
+		t.Error("Expected string to fail in strict mode")
+	}
+
+	err := h.Scan([]byte{0x5A, 0xC0})
+	if err != nil || !slices.Equal(h.Data, []int64{1, 1}) {
+		t.Errorf("Expected binary to be scanned in strict mode, got %v %v", h.Data, err)
+	}
+}
+
+func TestReparentedValueCopy(t *testing.T) {
+	var parent = HierarchyId{Data: make([]int64, 2, 10)}
+	parent.Data[0], parent.Data[1] = 1, 3
+
+	var a = HierarchyId{Data: []int64{1, 2, 5}}
+	var b = HierarchyId{Data: []int64{1, 2, 6, 1}}
+	var old = HierarchyId{Data: []int64{1, 2}}
+
+	var ra = a.GetReparentedValue(old, parent)
+	var rb = b.GetReparentedValue(old, parent)
+
+	if !slices.Equal(ra.Data, []int64{1, 3, 5}) || !slices.Equal(rb.Data, []int64{1, 3, 6, 1}) {
+		t.Fatalf("Reparenting under the same parent corrupted results %v %v", ra.Data, rb.Data)
+	}
+
+	ra.Data[0] = 9
+	rb.Data[1] = 9
+
+	if !slices.Equal(parent.Data, []int64{1, 3}) || !slices.Equal(a.Data, []int64{1, 2, 5}) || !slices.Equal(old.Data, []int64{1, 2}) {
+		t.Errorf("Changing results modified the inputs %v %v %v", parent.Data, a.Data, old.Data)
+	}
+}
+
+func TestAncestorsCopy(t *testing.T) {
+	var h = HierarchyId{Data: []int64{1, 2, 3}}
+
+	var parent = h.GetAncestor()
+	parent.Data[0] = 9
+
+	for _, a := range h.GetAncestors() {
+		a.Data[0] = 9
+	}
+
+	var lca = h.LowestCommonAncestor(HierarchyId{Data: []int64{1, 2, 4}})
+	lca.Data[0] = 9
+
+	var joined = h.Join(HierarchyId{Data: []int64{4}})
+	joined.Data[0] = 9
+
+	var stripped, _ = h.StripPrefix(HierarchyId{Data: []int64{1}})
+	stripped.Data[0] = 9
+
+	if !slices.Equal(h.Data, []int64{1, 2, 3}) {
+		t.Errorf("Changing results modified the input %v", h.Data)
+	}
+}
+
+func TestGetReparentedValue(t *testing.T) {
+	var old = HierarchyId{Data: []int64{1, 2}}
+	var new = HierarchyId{Data: []int64{3}}
+
+	var h = HierarchyId{Data: []int64{1, 2, 57, 8}}
+	var result = h.GetReparentedValue(old, new)
+	if !slices.Equal(result.Data, []int64{3, 57, 8}) {
+		t.Errorf("Expected /3/57/8/, got %v", result.Data)
+	}
+
+	result = old.GetReparentedValue(old, new)
+	if !slices.Equal(result.Data, []int64{3}) {
+		t.Errorf("Expected old ancestor to be moved to /3/, got %v", result.Data)
+	}
+
+	result = old.GetReparentedValue(old, GetRoot())
+	if !slices.Equal(result.Data, []int64{}) {
+		t.Errorf("Expected old ancestor to be moved to /, got %v", result.Data)
+	}
+
+	h = HierarchyId{Data: []int64{1, 3}}
+	result = h.GetReparentedValue(old, new)
+	if result.Data != nil {
+		t.Errorf("Expected no value for a node outside the old ancestor, got %v", result.Data)
+	}
+}
+
+type TestLineageStruct struct {
+	input  []int64
+	output []string
+}
+
+var TestLineageData []TestLineageStruct = []TestLineageStruct{
+	{[]int64{}, []string{"/"}},
+	{[]int64{1}, []string{"/", "/1/"}},
+	{[]int64{1, 2, 3}, []string{"/", "/1/", "/1/2/", "/1/2/3/"}},
+}
+
+func TestLineage(t *testing.T) {
+	for _, d := range TestLineageData {
+		var result = []string{}
+		for _, h := range lineage(HierarchyId{Data: d.input}) {
+			result = append(result, h.String())
+		}
+
+		if !slices.Equal(result, d.output) {
+			t.Errorf("Expected %v to return %v, got %v", d.input, d.output, result)
+		}
+	}
+}
@@ -0,0 +1,309 @@
+package hierarchyid
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// Index is an in-memory trie of hierarchyids keyed by level values, each entry holds a value of type V.
+//
+// Lookups of descendants and ancestors only visit the affected branch of the tree instead of every entry.
+//
+// Nodes are never modified after being created, writes copy the path from the root to the changed node (copy-on-write). Readers can use the index concurrently with writers and always observe a consistent version of the tree. The zero value is an empty index ready to use.
+type Index[V any] struct {
+	// Serializes writers, readers are lock free.
+	mutex sync.Mutex
+
+	// Root of the current version of the trie (nil if empty).
+	root atomic.Pointer[indexNode[V]]
+}
+
+// Node of the index trie, represents a level of the hierarchy.
+type indexNode[V any] struct {
+	// Value of the level represented by the node.
+	key int64
+
+	// Children of the node sorted by key.
+	children []*indexNode[V]
+
+	// Value stored in the node (if set).
+	value V
+
+	// Node has a value stored.
+	set bool
+
+	// Number of values stored in the node and all of its descendants.
+	count int
+}
+
+// Find the position of a child with the key (or where it should be inserted).
+func (n *indexNode[V]) search(key int64) (int, bool) {
+	var i = sort.Search(len(n.children), func(i int) bool {
+		return n.children[i].key >= key
+	})
+
+	return i, i < len(n.children) && n.children[i].key == key
+}
+
+// Get the node for the path (nil if not found).
+func (n *indexNode[V]) find(path HierarchyIdData) *indexNode[V] {
+	for _, level := range path {
+		if n == nil {
+			return nil
+		}
+
+		var i, found = n.search(level)
+		if !found {
+			return nil
+		}
+		n = n.children[i]
+	}
+
+	return n
+}
+
+// Insert a value returning a copy of the node with the change applied and if a new entry was added.
+func (n *indexNode[V]) insert(key int64, path HierarchyIdData, value V) (*indexNode[V], bool) {
+	var c = indexNode[V]{key: key}
+	if n != nil {
+		c = *n
+	}
+
+	if len(path) == 0 {
+		var added = !c.set
+		c.value = value
+		c.set = true
+		if added {
+			c.count++
+		}
+		return &c, added
+	}
+
+	var i, found = c.search(path[0])
+
+	var child *indexNode[V]
+	if found {
+		child = c.children[i]
+	}
+
+	var updated, added = child.insert(path[0], path[1:], value)
+
+	var children = make([]*indexNode[V], 0, len(c.children)+1)
+	children = append(children, c.children[:i]...)
+	children = append(children, updated)
+	if found {
+		children = append(children, c.children[i+1:]...)
+	} else {
+		children = append(children, c.children[i:]...)
+	}
+
+	c.children = children
+	if added {
+		c.count++
+	}
+
+	return &c, added
+}
+
+// Delete a value returning a copy of the node with the change applied (nil if the node became empty) and if an entry was removed.
+func (n *indexNode[V]) delete(path HierarchyIdData) (*indexNode[V], bool) {
+	if n == nil {
+		return nil, false
+	}
+
+	var c = *n
+
+	if len(path) == 0 {
+		if !c.set {
+			return n, false
+		}
+
+		var zero V
+		c.value = zero
+		c.set = false
+	} else {
+		var i, found = c.search(path[0])
+		if !found {
+			return n, false
+		}
+
+		var updated, removed = c.children[i].delete(path[1:])
+		if !removed {
+			return n, false
+		}
+
+		var children = make([]*indexNode[V], 0, len(c.children))
+		children = append(children, c.children[:i]...)
+		if updated != nil {
+			children = append(children, updated)
+		}
+		children = append(children, c.children[i+1:]...)
+		c.children = children
+	}
+
+	c.count--
+	if c.count == 0 {
+		return nil, true
+	}
+
+	return &c, true
+}
+
+// Visit the node and all of its descendants depth-first, path is the path of the node.
+//
+// Returns false if the visit was stopped.
+func (n *indexNode[V]) walk(path HierarchyIdData, self bool, yield func(HierarchyId, V) bool) bool {
+	if self && n.set {
+		if !yield(HierarchyId{Data: copyData(path)}, n.value) {
+			return false
+		}
+	}
+
+	for _, child := range n.children {
+		if !child.walk(append(path, child.key), true, yield) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Copy hierarchyid data into a new slice.
+func copyData(data HierarchyIdData) HierarchyIdData {
+	var c = make(HierarchyIdData, len(data))
+	copy(c, data)
+	return c
+}
+
+// Insert a value in the index, replaces the existing value if the hierarchyid is already present.
+func (i *Index[V]) Insert(h HierarchyId, value V) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	var root, _ = i.root.Load().insert(0, h.Data, value)
+	i.root.Store(root)
+}
+
+// Delete a hierarchyid from the index, returns false if it was not present.
+//
+// Only the entry is removed, entries of descendants are kept.
+func (i *Index[V]) Delete(h HierarchyId) bool {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	var root, removed = i.root.Load().delete(h.Data)
+	if removed {
+		i.root.Store(root)
+	}
+
+	return removed
+}
+
+// Get the value stored for a hierarchyid.
+func (i *Index[V]) Get(h HierarchyId) (V, bool) {
+	var n = i.root.Load().find(h.Data)
+	if n == nil || !n.set {
+		var zero V
+		return zero, false
+	}
+
+	return n.value, true
+}
+
+// Get the number of entries in the index.
+func (i *Index[V]) Len() int {
+	var root = i.root.Load()
+	if root == nil {
+		return 0
+	}
+
+	return root.count
+}
+
+// Count the entries that are descendants of a hierarchyid (the hierarchyid itself is not included).
+func (i *Index[V]) Count(h HierarchyId) int {
+	var n = i.root.Load().find(h.Data)
+	if n == nil {
+		return 0
+	}
+
+	if n.set {
+		return n.count - 1
+	}
+
+	return n.count
+}
+
+// Snapshot creates an independent copy of the index in constant time.
+//
+// Changes made to the index after the snapshot are not visible in the snapshot and vice versa.
+func (i *Index[V]) Snapshot() *Index[V] {
+	var s = &Index[V]{}
+	s.root.Store(i.root.Load())
+	return s
+}
+
+// All iterates over every entry of the index in depth-first order.
+//
+// The iterator is compatible with range-over-func (iter.Seq2).
+func (i *Index[V]) All() func(yield func(HierarchyId, V) bool) {
+	var root = i.root.Load()
+
+	return func(yield func(HierarchyId, V) bool) {
+		if root != nil {
+			root.walk(HierarchyIdData{}, true, yield)
+		}
+	}
+}
+
+// Descendants iterates over the entries that are descendants of a hierarchyid in depth-first order (the hierarchyid itself is not included).
+//
+// The iterator is compatible with range-over-func (iter.Seq2).
+func (i *Index[V]) Descendants(h HierarchyId) func(yield func(HierarchyId, V) bool) {
+	var n = i.root.Load().find(h.Data)
+	var path = copyData(h.Data)
+
+	return func(yield func(HierarchyId, V) bool) {
+		if n != nil {
+			n.walk(path, false, yield)
+		}
+	}
+}
+
+// Ancestors iterates over the entries that are ancestors of a hierarchyid, from the root to the direct parent.
+//
+// The iterator is compatible with range-over-func (iter.Seq2).
+func (i *Index[V]) Ancestors(h HierarchyId) func(yield func(HierarchyId, V) bool) {
+	var root = i.root.Load()
+	var path = copyData(h.Data)
+
+	return func(yield func(HierarchyId, V) bool) {
+		var n = root
+		for l := 0; l < len(path) && n != nil; l++ {
+			if n.set && !yield(HierarchyId{Data: copyData(path[:l])}, n.value) {
+				return
+			}
+
+			var c, found = n.search(path[l])
+			if !found {
+				return
+			}
+			n = n.children[c]
+		}
+	}
+}
+
+// Get the deepest entry that is an ancestor of a hierarchyid (the hierarchyid itself is not considered).
+func (i *Index[V]) NearestAncestor(h HierarchyId) (HierarchyId, V, bool) {
+	var nearest HierarchyId
+	var value V
+	var found = false
+
+	i.Ancestors(h)(func(a HierarchyId, v V) bool {
+		nearest, value, found = a, v, true
+		return true
+	})
+
+	return nearest, value, found
+}
@@ -0,0 +1,237 @@
+package hierarchyid
+
+import (
+	"slices"
+	"sync"
+	"testing"
+)
+
+// Collect the paths returned by an index iterator.
+func collectIndex(seq func(yield func(HierarchyId, string) bool)) []string {
+	var result = []string{}
+	seq(func(h HierarchyId, v string) bool {
+		result = append(result, ToString(h.Data)+"="+v)
+		return true
+	})
+	return result
+}
+
+func TestIndex(t *testing.T) {
+	var index = Index[string]{}
+
+	for _, p := range []string{"/2/", "/1/", "/1/2/", "/1/1/", "/1/1/5/", "/1/-1/", "/3/1/"} {
+		h, _ := FromString(p)
+		index.Insert(HierarchyId{Data: h}, p)
+	}
+
+	if index.Len() != 7 {
+		t.Errorf("Expected 7 entries, got %v", index.Len())
+	}
+
+	var all = collectIndex(index.All())
+	var expected = []string{"/1/=/1/", "/1/-1/=/1/-1/", "/1/1/=/1/1/", "/1/1/5/=/1/1/5/", "/1/2/=/1/2/", "/2/=/2/", "/3/1/=/3/1/"}
+	if !slices.Equal(all, expected) {
+		t.Errorf("Expected %v, got %v", expected, all)
+	}
+
+	var descendants = collectIndex(index.Descendants(HierarchyId{Data: []int64{1}}))
+	expected = []string{"/1/-1/=/1/-1/", "/1/1/=/1/1/", "/1/1/5/=/1/1/5/", "/1/2/=/1/2/"}
+	if !slices.Equal(descendants, expected) {
+		t.Errorf("Expected %v, got %v", expected, descendants)
+	}
+
+	if index.Count(HierarchyId{Data: []int64{1}}) != 4 || index.Count(HierarchyId{Data: []int64{3}}) != 1 || index.Count(GetRoot()) != 7 {
+		t.Error("Unexpected descendant count")
+	}
+
+	var ancestors = collectIndex(index.Ancestors(HierarchyId{Data: []int64{1, 1, 5, 7}}))
+	expected = []string{"/1/=/1/", "/1/1/=/1/1/", "/1/1/5/=/1/1/5/"}
+	if !slices.Equal(ancestors, expected) {
+		t.Errorf("Expected %v, got %v", expected, ancestors)
+	}
+
+	nearest, value, ok := index.NearestAncestor(HierarchyId{Data: []int64{3, 1, 4, 4}})
+	if !ok || !slices.Equal(nearest.Data, []int64{3, 1}) || value != "/3/1/" {
+		t.Errorf("Expected nearest ancestor /3/1/, got %v %v %v", nearest.Data, value, ok)
+	}
+
+	_, _, ok = index.NearestAncestor(HierarchyId{Data: []int64{4}})
+	if ok {
+		t.Error("Expected no ancestor for /4/")
+	}
+
+	value, ok = index.Get(HierarchyId{Data: []int64{1, 2}})
+	if !ok || value != "/1/2/" {
+		t.Errorf("Expected /1/2/, got %v %v", value, ok)
+	}
+
+	_, ok = index.Get(HierarchyId{Data: []int64{3}})
+	if ok {
+		t.Error("Expected /3/ to not be present")
+	}
+}
+
+func TestIndexDelete(t *testing.T) {
+	var index = Index[int]{}
+	index.Insert(HierarchyId{Data: []int64{1}}, 1)
+	index.Insert(HierarchyId{Data: []int64{1, 1}}, 2)
+	index.Insert(HierarchyId{Data: []int64{1, 1}}, 3)
+
+	if index.Len() != 2 {
+		t.Errorf("Expected 2 entries, got %v", index.Len())
+	}
+
+	if index.Delete(HierarchyId{Data: []int64{2}}) {
+		t.Error("Expected /2/ to not be deleted")
+	}
+
+	if !index.Delete(HierarchyId{Data: []int64{1}}) {
+		t.Error("Expected /1/ to be deleted")
+	}
+
+	value, ok := index.Get(HierarchyId{Data: []int64{1, 1}})
+	if !ok || value != 3 || index.Len() != 1 {
+		t.Errorf("Expected /1/1/ to be kept, got %v %v", value, ok)
+	}
+
+	index.Delete(HierarchyId{Data: []int64{1, 1}})
+	if index.Len() != 0 || index.root.Load() != nil {
+		t.Error("Expected index to be empty")
+	}
+}
+
+func TestIndexSnapshot(t *testing.T) {
+	var index = Index[int]{}
+	index.Insert(HierarchyId{Data: []int64{1}}, 1)
+
+	var snapshot = index.Snapshot()
+	var seq = index.All()
+
+	index.Insert(HierarchyId{Data: []int64{1, 1}}, 2)
+	index.Delete(HierarchyId{Data: []int64{1}})
+	snapshot.Insert(HierarchyId{Data: []int64{2}}, 3)
+
+	if snapshot.Len() != 2 || index.Len() != 1 {
+		t.Errorf("Expected snapshot and index to be independent, got %v %v", snapshot.Len(), index.Len())
+	}
+
+	var count = 0
+	seq(func(h HierarchyId, v int) bool {
+		count++
+		return true
+	})
+	if count != 1 {
+		t.Errorf("Expected iterator to use the version of the index when created, got %v entries", count)
+	}
+}
+
+func TestIndexConcurrent(t *testing.T) {
+	var index = Index[int]{}
+	var wg sync.WaitGroup
+
+	for w := 0; w < 4; w++ {
+		wg.Add(2)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				index.Insert(HierarchyId{Data: []int64{int64(w), int64(i)}}, i)
+			}
+		}(w)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				index.Count(HierarchyId{Data: []int64{int64(w)}})
+				index.NearestAncestor(HierarchyId{Data: []int64{int64(w), int64(i), 1}})
+			}
+		}(w)
+	}
+
+	wg.Wait()
+	if index.Len() != 800 {
+		t.Errorf("Expected 800 entries, got %v", index.Len())
+	}
+}
+
+// Create a tree with the fanout and depth provided.
+func benchmarkTree(fanout int, depth int) []HierarchyId {
+	var ids = []HierarchyId{}
+	var level = []HierarchyIdData{{}}
+
+	for d := 0; d < depth; d++ {
+		var next = []HierarchyIdData{}
+		for _, parent := range level {
+			for i := 1; i <= fanout; i++ {
+				var child = append(copyData(parent), int64(i))
+				next = append(next, child)
+				ids = append(ids, HierarchyId{Data: child})
+			}
+		}
+		level = next
+	}
+
+	return ids
+}
+
+func BenchmarkIndexCount(b *testing.B) {
+	var ids = benchmarkTree(10, 5)
+	var index = Index[int]{}
+	for i, h := range ids {
+		index.Insert(h, i)
+	}
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		index.Count(ids[n%len(ids)])
+	}
+}
+
+func BenchmarkLinearCount(b *testing.B) {
+	var ids = benchmarkTree(10, 5)
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		var parent = ids[n%len(ids)]
+		var count = 0
+		for _, h := range ids {
+			if h.IsDescendantOf(parent) {
+				count++
+			}
+		}
+	}
+}
+
+func BenchmarkIndexNearestAncestor(b *testing.B) {
+	var ids = benchmarkTree(10, 5)
+	var index = Index[int]{}
+	for i, h := range ids {
+		if h.GetLevel() < 3 {
+			index.Insert(h, i)
+		}
+	}
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		index.NearestAncestor(ids[n%len(ids)])
+	}
+}
+
+func BenchmarkLinearNearestAncestor(b *testing.B) {
+	var ids = benchmarkTree(10, 5)
+	var granted = []HierarchyId{}
+	for _, h := range ids {
+		if h.GetLevel() < 3 {
+			granted = append(granted, h)
+		}
+	}
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		var h = ids[n%len(ids)]
+		var nearest HierarchyId
+		for _, g := range granted {
+			if h.IsDescendantOf(g) && g.GetLevel() > nearest.GetLevel() {
+				nearest = g
+			}
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package hierarchyid
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Inheritance is the value of an attribute resolved from a node or its nearest ancestor that has a value.
+type Inheritance[V any] struct {
+	// Node the value was resolved for.
+	Node HierarchyId
+
+	// Inherited value (zero value if not found).
+	Value V
+
+	// Node that provided the value, the node itself or one of its ancestors.
+	Source HierarchyId
+
+	// A value was found in the node or one of its ancestors.
+	Found bool
+}
+
+// Get the nearest non-null value of a column from a node or its ancestors.
+//
+// The node and all of its ancestors (including a row at the root '/') are read in a single query, the deepest value found is used.
+func Inherited[V any](ctx context.Context, db *gorm.DB, model any, node HierarchyId, column string) (Inheritance[V], error) {
+	var result, err = InheritedBatch[V](ctx, db, model, []HierarchyId{node}, column)
+	if err != nil {
+		return Inheritance[V]{}, err
+	}
+
+	return result[0], nil
+}
+
+// Get the nearest non-null value of a column for multiple nodes, results are in the same order as the nodes.
+//
+// The nodes and their ancestors are read together, with one query for each BatchSize rows requested.
+func InheritedBatch[V any](ctx context.Context, db *gorm.DB, model any, nodes []HierarchyId, column string) ([]Inheritance[V], error) {
+	var t, err = newTree(ctx, db, model)
+	if err != nil {
+		return nil, err
+	}
+
+	var set = &Set{}
+	for _, n := range nodes {
+		for _, h := range lineage(n) {
+			err = set.Add(h)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var col = clause.Column{Name: column}
+	var levels = set.Values()
+	var index = &Index[V]{}
+
+	for start := 0; start < len(levels); start += BatchSize {
+		var end = start + BatchSize
+		if end > len(levels) {
+			end = len(levels)
+		}
+
+		rows, err := t.query(t.db).
+			Select("?, ?", t.column(), col).
+			Where("? IN ?", t.column(), levels[start:end]).
+			Where("? IS NOT NULL", col).
+			Rows()
+		if err != nil {
+			return nil, err
+		}
+
+		for rows.Next() {
+			var h HierarchyId
+			var value V
+
+			err = rows.Scan(&h, &value)
+			if err != nil {
+				rows.Close()
+				return nil, err
+			}
+
+			index.Insert(h, value)
+		}
+
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var result = make([]Inheritance[V], len(nodes))
+	for i, n := range nodes {
+		result[i] = Inherit(index, n, func(v V) (V, bool) {
+			return v, true
+		})
+	}
+
+	return result, nil
+}
+
+// Get the nearest value of an attribute from a node or its ancestors stored in an index.
+//
+// The attribute function returns the value of the attribute of an entry and false if the entry has no value (e.g. a nil pointer).
+func Inherit[V any, A any](index *Index[V], node HierarchyId, attr func(V) (A, bool)) Inheritance[A] {
+	var result = Inheritance[A]{Node: HierarchyId{Data: copyData(node.Data)}}
+
+	var check = func(h HierarchyId, v V) bool {
+		if a, ok := attr(v); ok {
+			result.Value, result.Source, result.Found = a, h, true
+		}
+		return true
+	}
+
+	index.Ancestors(node)(check)
+
+	if v, ok := index.Get(node); ok {
+		check(HierarchyId{Data: copyData(node.Data)}, v)
+	}
+
+	return result
+}
@@ -0,0 +1,86 @@
+package hierarchyid
+
+import (
+	"testing"
+)
+
+type TestInheritStruct struct {
+	// Node to resolve
+	node string
+
+	// Expected source of the value (empty if not found)
+	source string
+
+	// Expected value
+	value string
+}
+
+var TestInheritData = []TestInheritStruct{
+	{node: "/1/", source: "/1/", value: "UTC"},
+	{node: "/1/1/", source: "/1/", value: "UTC"},
+	{node: "/1/2/", source: "/1/2/", value: "Europe/Lisbon"},
+	{node: "/1/2/3/4/", source: "/1/2/", value: "Europe/Lisbon"},
+	{node: "/1/3/", source: "/1/", value: "UTC"},
+	{node: "/2/", source: "", value: ""},
+}
+
+func TestInherit(t *testing.T) {
+	var index = Index[*string]{}
+
+	var timezones = map[string]*string{}
+	var utc, lisbon = "UTC", "Europe/Lisbon"
+	timezones["/1/"] = &utc
+	timezones["/1/2/"] = &lisbon
+	timezones["/1/3/"] = nil
+	timezones["/2/"] = nil
+
+	for p, v := range timezones {
+		h, _ := FromString(p)
+		index.Insert(HierarchyId{Data: h}, v)
+	}
+
+	for _, test := range TestInheritData {
+		h, _ := FromString(test.node)
+
+		var result = Inherit(&index, HierarchyId{Data: h}, func(v *string) (string, bool) {
+			if v == nil {
+				return "", false
+			}
+			return *v, true
+		})
+
+		if result.Found != (test.source != "") {
+			t.Errorf("Expected found %v for %s", test.source != "", test.node)
+		}
+
+		if result.Found && ToString(result.Source.Data) != test.source {
+			t.Errorf("Expected source %s for %s, got %s", test.source, test.node, ToString(result.Source.Data))
+		}
+
+		if result.Value != test.value {
+			t.Errorf("Expected value %s for %s, got %s", test.value, test.node, result.Value)
+		}
+	}
+}
+
+func TestInheritCopy(t *testing.T) {
+	var index = Index[string]{}
+	index.Insert(HierarchyId{Data: []int64{1}}, "UTC")
+
+	var node = HierarchyId{Data: []int64{1, 2}}
+	var result = Inherit(&index, node, func(v string) (string, bool) {
+		return v, true
+	})
+
+	result.Node.Data[0] = 9
+	result.Source.Data[0] = 9
+
+	if node.String() != "/1/2/" {
+		t.Errorf("Changing the result modified the input %v", node.Data)
+	}
+
+	source, _ := index.Get(HierarchyId{Data: []int64{1}})
+	if source != "UTC" || index.Len() != 1 {
+		t.Errorf("Changing the result modified the index")
+	}
+}
@@ -0,0 +1,105 @@
+package hierarchyid
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+)
+
+// Key is a comparable and immutable representation of a hierarchyid.
+//
+// Stores the SQL Server binary encoding, can be used as map key (including JSON map keys) and compared with '=='. Keys sort (see Compare) in the same depth-first order as the hierarchyids.
+type Key struct {
+	data string
+}
+
+// Get the key of the hierarchyid.
+//
+// Fails if a level is out of the range supported by the binary format.
+func (j HierarchyId) Key() (Key, error) {
+	var data, err = Encode(j.Data)
+	if err != nil {
+		return Key{}, err
+	}
+
+	return Key{data: string(data)}, nil
+}
+
+// Get the hierarchyid represented by the key.
+func (k Key) HierarchyId() (HierarchyId, error) {
+	var data, err = Decode([]byte(k.data))
+	if err != nil {
+		return HierarchyId{}, err
+	}
+
+	return HierarchyId{Data: data}, nil
+}
+
+// String returns the path of the hierarchyid represented by the key (e.g. '/1/2/').
+func (k Key) String() string {
+	var data, err = Decode([]byte(k.data))
+	if err != nil {
+		return "%!(invalid hierarchyid key)"
+	}
+
+	return ToString(data)
+}
+
+// Compare two keys, returns -1, 0 or 1 following the depth-first order of the hierarchyids.
+func (k Key) Compare(other Key) int {
+	return strings.Compare(k.data, other.data)
+}
+
+// MarshalText implements the encoding.TextMarshaler interface, the text is the path of the hierarchyid (e.g. '/1/2/').
+func (k Key) MarshalText() ([]byte, error) {
+	var data, err = Decode([]byte(k.data))
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(ToString(data)), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (k *Key) UnmarshalText(text []byte) error {
+	var data, err = FromString(string(text))
+	if err != nil {
+		return err
+	}
+
+	key, err := HierarchyId{Data: data}.Key()
+	if err != nil {
+		return err
+	}
+
+	*k = key
+	return nil
+}
+
+// Version byte placed before the key in cursor tokens, keeps the token of the root different from the empty token.
+const cursorVersion byte = 1
+
+// Get an opaque token of the key that can be used as pagination cursor (e.g. in URLs).
+func (k Key) Cursor() string {
+	return base64.RawURLEncoding.EncodeToString(append([]byte{cursorVersion}, k.data...))
+}
+
+// Get the hierarchyid of a cursor token created with Cursor.
+//
+// An empty token returns a hierarchyid without data, used to start from the first page.
+func ParseCursor(token string) (HierarchyId, error) {
+	if token == "" {
+		return HierarchyId{}, nil
+	}
+
+	var data, err = base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return HierarchyId{}, err
+	}
+
+	if len(data) == 0 || data[0] != cursorVersion {
+		return HierarchyId{}, errors.New("hierarchyid: invalid cursor")
+	}
+
+	return Key{data: string(data[1:])}.HierarchyId()
+}
@@ -0,0 +1,124 @@
+package hierarchyid
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestKey(t *testing.T) {
+	var keys = []Key{}
+
+	for _, d := range TestEncodeDecodeData {
+		key, err := HierarchyId{Data: d.output}.Key()
+		if err != nil {
+			t.Errorf("Error creating key for %v: %v", d.output, err)
+		}
+
+		h, err := key.HierarchyId()
+		if err != nil || !slices.Equal(h.Data, d.output) {
+			t.Errorf("Expected key of %v to return %v, got %v %v", d.output, d.output, h.Data, err)
+		}
+
+		if key.String() != ToString(d.output) {
+			t.Errorf("Expected key string %v, got %v", ToString(d.output), key.String())
+		}
+
+		keys = append(keys, key)
+	}
+
+	// Keys should sort in depth-first order
+	sort.Slice(keys, func(a, b int) bool { return keys[a].Compare(keys[b]) < 0 })
+	for i := 1; i < len(keys); i++ {
+		a, _ := keys[i-1].HierarchyId()
+		b, _ := keys[i].HierarchyId()
+		if CompareDepthFirst(a.Data, b.Data) > 0 {
+			t.Errorf("Expected %v to sort before %v", a.Data, b.Data)
+		}
+	}
+
+	var m = map[Key]bool{}
+	k1, _ := HierarchyId{Data: []int64{1, 2}}.Key()
+	k2, _ := HierarchyId{Data: []int64{1, 2}}.Key()
+	m[k1] = true
+	if !m[k2] {
+		t.Error("Expected equal hierarchyids to have equal keys")
+	}
+
+	_, err := HierarchyId{Data: []int64{281479271683152}}.Key()
+	if err == nil {
+		t.Error("Expected error for out of range level")
+	}
+}
+
+func TestKeyCursor(t *testing.T) {
+	for _, d := range TestEncodeDecodeData {
+		key, _ := HierarchyId{Data: d.output}.Key()
+
+		var cursor = key.Cursor()
+		if strings.ContainsAny(cursor, "+/=") {
+			t.Errorf("Expected URL safe cursor, got %v", cursor)
+		}
+
+		h, err := ParseCursor(cursor)
+		if err != nil || !slices.Equal(h.Data, d.output) {
+			t.Errorf("Expected cursor of %v to return %v, got %v %v", d.output, d.output, h.Data, err)
+		}
+	}
+
+	h, err := ParseCursor("")
+	if err != nil || h.Data != nil {
+		t.Errorf("Expected empty cursor to start from the first row, got %v %v", h.Data, err)
+	}
+
+	// The root has an empty key, its cursor must not start from the first row again
+	root, _ := GetRoot().Key()
+	if root.Cursor() == "" {
+		t.Errorf("Expected the cursor of the root to differ from the empty cursor")
+	}
+
+	h, err = ParseCursor(root.Cursor())
+	if err != nil || h.Data == nil || len(h.Data) != 0 {
+		t.Errorf("Expected cursor of the root to return the root, got %v %v", h.Data, err)
+	}
+
+	_, err = ParseCursor("AA")
+	if err == nil {
+		t.Errorf("Expected error for cursor with unknown version")
+	}
+
+	_, err = ParseCursor("not a cursor!")
+	if err == nil {
+		t.Errorf("Expected error for invalid cursor")
+	}
+}
+
+func TestKeyJSONMap(t *testing.T) {
+	var m = map[Key]int{}
+	for i, d := range TestEncodeDecodeData {
+		key, _ := HierarchyId{Data: d.output}.Key()
+		m[key] = i
+	}
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal("Failed to marshal map", err)
+	}
+
+	if !strings.Contains(string(data), `"/1/2/754/":`) {
+		t.Errorf("Expected path keys, got %s", data)
+	}
+
+	var result = map[Key]int{}
+	err = json.Unmarshal(data, &result)
+	if err != nil {
+		t.Fatal("Failed to unmarshal map", err)
+	}
+
+	if !maps.Equal(m, result) {
+		t.Errorf("Expected %v, got %v", m, result)
+	}
+}
@@ -0,0 +1,102 @@
+package hierarchyid
+
+import (
+	"strconv"
+)
+
+// Maximum size in bytes of a hierarchyid supported by SQL Server.
+const MaxBytes = 892
+
+// Limits applied to hierarchyids before they are stored.
+//
+// Zero values are not checked.
+type Limits struct {
+	// Maximum size of the encoded value in bytes.
+	MaxBytes int
+
+	// Maximum number of levels.
+	MaxDepth int
+
+	// Maximum absolute value of each level, limits the number of siblings (e.g. 1000 allows values from -1000 to 1000).
+	MaxFanout int64
+}
+
+// Limits checked by Value() before sending a hierarchyid to the database.
+//
+// By default only the SQL Server size limit is checked, can be changed to reject deep or wide trees before reaching the database.
+var DefaultLimits = Limits{MaxBytes: MaxBytes}
+
+// LimitError is returned when a hierarchyid exceeds one of the Limits.
+type LimitError struct {
+	// Name of the limit exceeded ("MaxBytes", "MaxDepth" or "MaxFanout").
+	Limit string
+
+	// Value of the limit.
+	Max int64
+
+	// Value found in the hierarchyid.
+	Actual int64
+}
+
+// Error implements the error interface.
+func (e *LimitError) Error() string {
+	return "hierarchyid: " + e.Limit + " exceeded, limit is " + strconv.FormatInt(e.Max, 10) + " got " + strconv.FormatInt(e.Actual, 10)
+}
+
+// RangeError is returned when a level value is out of the range supported by the binary format.
+type RangeError struct {
+	// Index of the level
+	Level int
+
+	// Value of the level
+	Value int64
+}
+
+// Error implements the error interface.
+func (e *RangeError) Error() string {
+	return "hierarchyid: no pattern found for " + strconv.FormatInt(e.Value, 10) + " at level " + strconv.Itoa(e.Level)
+}
+
+// Calculate the size in bytes of the encoded hierarchyid without encoding it.
+func EncodedSize(h HierarchyId) (int, error) {
+	var bits = 0
+
+	for l, level := range h.Data {
+		var pattern = findPattern(level)
+		if pattern == nil {
+			return 0, &RangeError{Level: l, Value: level}
+		}
+
+		bits += len(pattern.Pattern)
+	}
+
+	return (bits + 7) / 8, nil
+}
+
+// Check if a hierarchyid respects the limits provided.
+//
+// Returns a *LimitError if a limit is exceeded or a *RangeError if a level cannot be encoded.
+func Validate(h HierarchyId, limits Limits) error {
+	if limits.MaxDepth > 0 && len(h.Data) > limits.MaxDepth {
+		return &LimitError{Limit: "MaxDepth", Max: int64(limits.MaxDepth), Actual: int64(len(h.Data))}
+	}
+
+	if limits.MaxFanout > 0 {
+		for _, level := range h.Data {
+			if level > limits.MaxFanout || level < -limits.MaxFanout {
+				return &LimitError{Limit: "MaxFanout", Max: limits.MaxFanout, Actual: level}
+			}
+		}
+	}
+
+	var size, err = EncodedSize(h)
+	if err != nil {
+		return err
+	}
+
+	if limits.MaxBytes > 0 && size > limits.MaxBytes {
+		return &LimitError{Limit: "MaxBytes", Max: int64(limits.MaxBytes), Actual: int64(size)}
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package hierarchyid
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestEncodedSize(t *testing.T) {
+	for _, d := range TestEncodeDecodeData {
+		size, err := EncodedSize(HierarchyId{Data: d.output})
+		if err != nil {
+			t.Errorf("Error calculating size of %v: %v", d.output, err)
+		}
+
+		if size != len(d.input)/2 {
+			t.Errorf("Expected %v to have %v bytes, got %v", d.output, len(d.input)/2, size)
+		}
+	}
+
+	_, err := EncodedSize(HierarchyId{Data: []int64{1, 281479271683152}})
+	var rerr *RangeError
+	if !errors.As(err, &rerr) || rerr.Level != 1 {
+		t.Errorf("Expected range error at level 1, got %v", err)
+	}
+}
+
+type TestValidateStruct struct {
+	input  []int64
+	limits Limits
+	limit  string
+}
+
+var TestValidateData []TestValidateStruct = []TestValidateStruct{
+	{[]int64{1, 2, 3}, Limits{}, ""},
+	{[]int64{1, 2, 3}, Limits{MaxDepth: 3}, ""},
+	{[]int64{1, 2, 3}, Limits{MaxDepth: 2}, "MaxDepth"},
+	{[]int64{1, 200, 3}, Limits{MaxFanout: 100}, "MaxFanout"},
+	{[]int64{1, -200, 3}, Limits{MaxFanout: 100}, "MaxFanout"},
+	{[]int64{1, 100, -100}, Limits{MaxFanout: 100}, ""},
+	{[]int64{5200}, Limits{MaxBytes: 5}, "MaxBytes"},
+	{[]int64{5200}, Limits{MaxBytes: 6}, ""},
+}
+
+func TestValidate(t *testing.T) {
+	for _, d := range TestValidateData {
+		err := Validate(HierarchyId{Data: d.input}, d.limits)
+
+		if d.limit == "" {
+			if err != nil {
+				t.Errorf("Expected %v to be valid, got %v", d.input, err)
+			}
+			continue
+		}
+
+		var lerr *LimitError
+		if !errors.As(err, &lerr) || lerr.Limit != d.limit {
+			t.Errorf("Expected %v to exceed %v, got %v", d.input, d.limit, err)
+		}
+	}
+}
+
+func TestValueLimits(t *testing.T) {
+	// 200 levels of 5200 use 43 bits each (1075 bytes)
+	var data = make([]int64, 200)
+	for i := range data {
+		data[i] = 5200
+	}
+
+	_, err := HierarchyId{Data: data}.Value()
+	var lerr *LimitError
+	if !errors.As(err, &lerr) || lerr.Limit != "MaxBytes" || lerr.Actual != 1075 {
+		t.Errorf("Expected MaxBytes error, got %v", err)
+	}
+
+	DefaultLimits = Limits{MaxBytes: MaxBytes, MaxDepth: 2}
+	defer func() { DefaultLimits = Limits{MaxBytes: MaxBytes} }()
+
+	_, err = HierarchyId{Data: []int64{1, 2, 3}}.Value()
+	if !errors.As(err, &lerr) || lerr.Limit != "MaxDepth" {
+		t.Errorf("Expected MaxDepth error, got %v", err)
+	}
+}
@@ -0,0 +1,119 @@
+package hierarchyid
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Returned when more than one sibling has the name used in a name path.
+var ErrAmbiguousName = errors.New("hierarchyid: more than one sibling with the same name")
+
+// Get the hierarchyid of the node at a path of names (e.g. 'Engineering/Platform/Databases').
+//
+// Names are separated by '/', leading and trailing separators are ignored. The path is resolved from the root with one query per level using the name column provided.
+//
+// Returns gorm.ErrRecordNotFound if a level does not exist and ErrAmbiguousName if a level has more than one sibling with the name.
+func ResolveNamePath(ctx context.Context, db *gorm.DB, model any, nameColumn string, path string) (HierarchyId, error) {
+	var t, err = newTree(ctx, db, model)
+	if err != nil {
+		return HierarchyId{}, err
+	}
+
+	var node = HierarchyId{Data: []int64{}}
+
+	for _, name := range strings.Split(path, "/") {
+		if name == "" {
+			continue
+		}
+
+		var children = []HierarchyId{}
+
+		err := t.query(t.db).
+			Where("?.GetAncestor(1) = CAST(? AS hierarchyid)", t.column(), node).
+			Where(clause.Eq{Column: clause.Column{Name: nameColumn}, Value: name}).
+			Limit(2).
+			Pluck(t.path.DBName, &children).Error
+		if err != nil {
+			return HierarchyId{}, err
+		}
+
+		if len(children) == 0 {
+			return HierarchyId{}, gorm.ErrRecordNotFound
+		}
+		if len(children) > 1 {
+			return HierarchyId{}, ErrAmbiguousName
+		}
+
+		node = children[0]
+	}
+
+	return node, nil
+}
+
+// Get the rows of a node and all of its ancestors (including a row at the root '/') in a single query, sorted from the root to the node.
+//
+// Used to build breadcrumbs, the labels of each level are read from the rows.
+func Breadcrumb[T any](ctx context.Context, db *gorm.DB, model *T, node HierarchyId) ([]T, error) {
+	var t, err = newTree(ctx, db, model)
+	if err != nil {
+		return nil, err
+	}
+
+	var levels = lineage(node)
+	var rows = []T{}
+
+	err = t.query(t.db).
+		Where("? IN ?", t.column(), levels).
+		Order(clause.OrderByColumn{Column: t.column()}).
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// Enforce unique names between siblings in the database.
+//
+// A persisted computed column with the parent of each row ('<path>_parent') and a unique index on the parent and the name column (and the tree column if the model has one) are created (if they do not exist yet). For models with soft delete the index only includes rows that are not deleted.
+func UniqueSiblingNames(ctx context.Context, db *gorm.DB, model any, nameColumn string) error {
+	var t, err = newTree(ctx, db, model)
+	if err != nil {
+		return err
+	}
+
+	var parent = t.path.DBName + "_parent"
+	var index = "idx_" + t.schema.Table + "_" + parent + "_" + nameColumn
+	var migrator = t.db.Migrator()
+
+	if !migrator.HasColumn(t.model, parent) {
+		err = t.db.Exec("ALTER TABLE ? ADD ? AS ?.GetAncestor(1) PERSISTED",
+			clause.Table{Name: t.schema.Table}, clause.Column{Name: parent}, t.column()).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	if migrator.HasIndex(t.model, index) {
+		return nil
+	}
+
+	var columns = []clause.Column{{Name: parent}, {Name: nameColumn}}
+	if t.treeField != nil {
+		columns = append([]clause.Column{{Name: t.treeField.DBName}}, columns...)
+	}
+
+	var sql = "CREATE UNIQUE INDEX ? ON ? ?"
+	var vars = []any{clause.Column{Name: index}, clause.Table{Name: t.schema.Table}, columns}
+
+	if deletedAt := t.deletedAt(); deletedAt != nil {
+		sql += " WHERE ? IS NULL"
+		vars = append(vars, clause.Column{Name: deletedAt.DBName})
+	}
+
+	return t.db.Exec(sql, vars...).Error
+}
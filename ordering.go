@@ -0,0 +1,317 @@
+package hierarchyid
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+
+	"gorm.io/gorm"
+)
+
+// Returned when a node is moved to a position inside of its own subtree.
+var ErrInvalidMove = errors.New("hierarchyid: cannot move a node into its own subtree")
+
+// Returned when an operation requires nodes with the same parent.
+var ErrNotSiblings = errors.New("hierarchyid: nodes do not have the same parent")
+
+// Siblings moved to make room for a new key, all siblings with value equal or above start were moved one value up.
+type room struct {
+	parent HierarchyId
+	start  int64
+	moved  bool
+}
+
+// Apply the renumbering of the room to a hierarchyid of the tree, used to keep track of nodes that were moved.
+func (r room) apply(h HierarchyId) HierarchyId {
+	var level = len(r.parent.Data)
+	if !r.moved || !h.IsDescendantOf(r.parent) || h.Data[level] < r.start {
+		return h
+	}
+
+	var data = copyData(h.Data)
+	data[level]++
+	return HierarchyId{Data: data}
+}
+
+// Get a free key between two sibling values of a parent (nil for no bound).
+//
+// Uses the value in the middle of the gap, when there is no gap the siblings from hi onwards are moved one value up.
+func (t *tree) keyBetween(tx *gorm.DB, parent HierarchyId, lo *int64, hi *int64) (HierarchyId, room, error) {
+	switch {
+	case lo == nil && hi == nil:
+		return parent.Child(1), room{}, nil
+	case lo == nil:
+		var value, err = checkSiblingValue(*hi - 1)
+		return parent.Child(value), room{}, err
+	case hi == nil:
+		var value, err = checkSiblingValue(*lo + 1)
+		return parent.Child(value), room{}, err
+	case *hi-*lo > 1:
+		return parent.Child(*lo + (*hi-*lo)/2), room{}, nil
+	}
+
+	var r = room{parent: parent, start: *hi, moved: true}
+	err := t.makeRoom(tx, r)
+	if err != nil {
+		return HierarchyId{}, room{}, err
+	}
+
+	return parent.Child(*hi), r, nil
+}
+
+// Move all siblings with value equal or above the start of the room one value up.
+//
+// Siblings are moved from the last to the first to never collide with an existing key.
+func (t *tree) makeRoom(tx *gorm.DB, r room) error {
+	var values, err = t.childValues(tx, r.parent)
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(values, func(a, b int) bool { return values[a] > values[b] })
+
+	for _, v := range values {
+		if v < r.start {
+			break
+		}
+
+		var next, err = checkSiblingValue(v + 1)
+		if err != nil {
+			return err
+		}
+
+		err = t.moveSubtree(tx, r.parent.Child(v), r.parent.Child(next))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Get a free key right before or after an existing sibling.
+func (t *tree) keyNextTo(tx *gorm.DB, sibling HierarchyId, after bool) (HierarchyId, room, error) {
+	if sibling.GetLevel() == 0 {
+		return HierarchyId{}, room{}, errors.New("hierarchyid: the root has no siblings")
+	}
+
+	var parent = sibling.GetAncestor()
+	var value = sibling.Data[len(sibling.Data)-1]
+
+	var values, err = t.childValues(tx, parent)
+	if err != nil {
+		return HierarchyId{}, room{}, err
+	}
+
+	// Find the closest siblings on each side
+	var lo, hi *int64
+	for i := range values {
+		var v = values[i]
+		if v < value && (lo == nil || v > *lo) {
+			lo = &values[i]
+		}
+		if v > value && (hi == nil || v < *hi) {
+			hi = &values[i]
+		}
+	}
+
+	if after {
+		return t.keyBetween(tx, parent, &value, hi)
+	}
+
+	return t.keyBetween(tx, parent, lo, &value)
+}
+
+// Insert a row right before a sibling.
+//
+// The key of the row is placed between the sibling and the previous one, if there is no gap between them the following siblings and their subtrees are renumbered in the same transaction.
+func InsertBefore(ctx context.Context, db *gorm.DB, model any, sibling HierarchyId, row any) error {
+	return insertNextTo(ctx, db, model, sibling, row, false)
+}
+
+// Insert a row right after a sibling.
+//
+// The key of the row is placed between the sibling and the next one, if there is no gap between them the following siblings and their subtrees are renumbered in the same transaction.
+func InsertAfter(ctx context.Context, db *gorm.DB, model any, sibling HierarchyId, row any) error {
+	return insertNextTo(ctx, db, model, sibling, row, true)
+}
+
+// Insert a row before or after a sibling.
+func insertNextTo(ctx context.Context, db *gorm.DB, model any, sibling HierarchyId, row any, after bool) error {
+	var t, err = newTree(ctx, db, model)
+	if err != nil {
+		return err
+	}
+
+	return t.db.Transaction(func(tx *gorm.DB) error {
+		var key, _, err = t.keyNextTo(tx, sibling, after)
+		if err != nil {
+			return err
+		}
+
+		err = t.setPath(row, key)
+		if err != nil {
+			return err
+		}
+
+		err = t.setTree(row)
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(row).Error
+	})
+}
+
+// Move a node (and its subtree) to the position right before a sibling.
+func MoveBefore(ctx context.Context, db *gorm.DB, model any, node HierarchyId, sibling HierarchyId) error {
+	return moveNextTo(ctx, db, model, node, sibling, false)
+}
+
+// Move a node (and its subtree) to the position right after a sibling.
+func MoveAfter(ctx context.Context, db *gorm.DB, model any, node HierarchyId, sibling HierarchyId) error {
+	return moveNextTo(ctx, db, model, node, sibling, true)
+}
+
+// Move a node before or after a sibling.
+func moveNextTo(ctx context.Context, db *gorm.DB, model any, node HierarchyId, sibling HierarchyId, after bool) error {
+	if slices.Equal(node.Data, sibling.Data) {
+		return nil
+	}
+
+	if sibling.IsDescendantOf(node) {
+		return ErrInvalidMove
+	}
+
+	var t, err = newTree(ctx, db, model)
+	if err != nil {
+		return err
+	}
+
+	return t.db.Transaction(func(tx *gorm.DB) error {
+		var key, r, err = t.keyNextTo(tx, sibling, after)
+		if err != nil {
+			return err
+		}
+
+		// The node might have been renumbered to make room for the key
+		return t.moveSubtree(tx, r.apply(node), key)
+	})
+}
+
+// Swap the position of two siblings (and their subtrees).
+func SwapSiblings(ctx context.Context, db *gorm.DB, model any, a HierarchyId, b HierarchyId) error {
+	if a.GetLevel() == 0 || b.GetLevel() == 0 || !slices.Equal(a.GetAncestor().Data, b.GetAncestor().Data) {
+		return ErrNotSiblings
+	}
+
+	if slices.Equal(a.Data, b.Data) {
+		return nil
+	}
+
+	var t, err = newTree(ctx, db, model)
+	if err != nil {
+		return err
+	}
+
+	return t.db.Transaction(func(tx *gorm.DB) error {
+		var parent = a.GetAncestor()
+
+		var values, err = t.childValues(tx, parent)
+		if err != nil {
+			return err
+		}
+
+		// Park the first node in a free key while the second takes its place
+		value, err := DenseAllocator{}.Next(values)
+		if err != nil {
+			return err
+		}
+		var temp = parent.Child(value)
+
+		err = t.moveSubtree(tx, a, temp)
+		if err != nil {
+			return err
+		}
+
+		err = t.moveSubtree(tx, b, a)
+		if err != nil {
+			return err
+		}
+
+		return t.moveSubtree(tx, temp, b)
+	})
+}
+
+// Renumber the children of a node so that the depth-first order matches a column order (e.g. "name ASC").
+//
+// Children are numbered 1, 2, 3, etc and their subtrees are moved with them in a single transaction. When recursive is set every level below the node is also sorted.
+//
+// The orderBy expression is added to the query as is (same as gorm Order), it must not contain user input.
+func SortChildren(ctx context.Context, db *gorm.DB, model any, parent HierarchyId, orderBy string, recursive bool) error {
+	var t, err = newTree(ctx, db, model)
+	if err != nil {
+		return err
+	}
+
+	return t.db.Transaction(func(tx *gorm.DB) error {
+		return t.sortChildren(tx, parent, orderBy, recursive)
+	})
+}
+
+// Renumber the children of a node following the order provided.
+func (t *tree) sortChildren(tx *gorm.DB, parent HierarchyId, orderBy string, recursive bool) error {
+	var children = []HierarchyId{}
+
+	err := t.query(tx).Unscoped().
+		Where("?.GetAncestor(1) = CAST(? AS hierarchyid)", t.column(), parent).
+		Order(orderBy).
+		Pluck(t.path.DBName, &children).Error
+	if err != nil {
+		return err
+	}
+
+	if len(children) == 0 {
+		return nil
+	}
+
+	// Park all children after the largest value in use, then move them to the final position
+	var park = int64(len(children))
+	for _, c := range children {
+		var v = c.Data[len(c.Data)-1]
+		if v > park {
+			park = v
+		}
+	}
+
+	for i, c := range children {
+		var value, err = checkSiblingValue(park + 1 + int64(i))
+		if err != nil {
+			return err
+		}
+
+		err = t.moveSubtree(tx, c, parent.Child(value))
+		if err != nil {
+			return err
+		}
+	}
+
+	for i := range children {
+		var final = parent.Child(int64(i) + 1)
+
+		err = t.moveSubtree(tx, parent.Child(park+1+int64(i)), final)
+		if err != nil {
+			return err
+		}
+
+		if recursive {
+			err = t.sortChildren(tx, final, orderBy, recursive)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
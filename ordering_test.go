@@ -0,0 +1,56 @@
+package hierarchyid
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+type TestRoomApplyStruct struct {
+	input  []int64
+	output []int64
+}
+
+var TestRoomApplyData []TestRoomApplyStruct = []TestRoomApplyStruct{
+	{[]int64{1, 2}, []int64{1, 3}},
+	{[]int64{1, 5, 3}, []int64{1, 6, 3}},
+	{[]int64{1, 1}, []int64{1, 1}},
+	{[]int64{1}, []int64{1}},
+	{[]int64{2, 2}, []int64{2, 2}},
+}
+
+func TestRoomApply(t *testing.T) {
+	var r = room{parent: HierarchyId{Data: []int64{1}}, start: 2, moved: true}
+
+	for _, d := range TestRoomApplyData {
+		var result = r.apply(HierarchyId{Data: d.input})
+
+		if !slices.Equal(result.Data, d.output) {
+			t.Errorf("Expected %v to return %v, got %v", d.input, d.output, result.Data)
+		}
+	}
+}
+
+type TestSwapSiblingsStruct struct {
+	a []int64
+	b []int64
+}
+
+var TestSwapSiblingsData []TestSwapSiblingsStruct = []TestSwapSiblingsStruct{
+	{[]int64{}, []int64{1}},
+	{[]int64{1}, []int64{}},
+	{[]int64{}, []int64{}},
+	{[]int64{1}, []int64{1, 2}},
+	{[]int64{1, 1}, []int64{2, 1}},
+}
+
+func TestSwapSiblingsNotSiblings(t *testing.T) {
+	for _, d := range TestSwapSiblingsData {
+		var err = SwapSiblings(context.Background(), nil, nil, HierarchyId{Data: d.a}, HierarchyId{Data: d.b})
+
+		if !errors.Is(err, ErrNotSiblings) {
+			t.Errorf("Expected %v and %v to return ErrNotSiblings, got %v", d.a, d.b, err)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package hierarchyid
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Options used to parse the text representation of hierarchyids.
+type ParseOptions struct {
+	// Only accept the canonical representation.
+	//
+	// With the default separator the text must start and end with '/' (e.g. '/1/2/'), with other separators the text must not start or end with it (e.g. '1.2'). Empty levels, '+' signs, negative zero and leading zeros are rejected.
+	Strict bool
+
+	// Character used to separate levels, '/' when not set.
+	//
+	// Alternate notations such as '1.2.3' or '1>2>3' can be parsed using '.' or '>'.
+	Separator byte
+
+	// Accept zero padded levels (e.g. '0001/0002') in strict mode.
+	AllowZeroPadding bool
+
+	// Maximum number of levels (0 for no limit).
+	MaxDepth int
+
+	// Maximum length of the text in bytes (0 for no limit).
+	MaxLength int
+}
+
+// ParseError describes a problem found when parsing a hierarchyid.
+type ParseError struct {
+	// Text being parsed
+	Input string
+
+	// Position (byte offset) in the text where the problem was found
+	Pos int
+
+	// Description of the problem
+	Msg string
+
+	// Underlying error (if any)
+	Err error
+}
+
+// Error implements the error interface.
+func (e *ParseError) Error() string {
+	return "hierarchyid: invalid " + strconv.Quote(e.Input) + " at position " + strconv.Itoa(e.Pos) + ": " + e.Msg
+}
+
+// Unwrap returns the underlying error.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Parse the text representation of a hierarchyid.
+//
+// Unlike FromString the parser reports the position of problems found, limits the size of the input and can be configured to only accept the canonical form.
+func Parse(s string, opts ParseOptions) (HierarchyId, error) {
+	var sep = opts.Separator
+	if sep == 0 {
+		sep = '/'
+	}
+
+	if opts.MaxLength > 0 && len(s) > opts.MaxLength {
+		return HierarchyId{}, &ParseError{Input: s, Pos: opts.MaxLength, Msg: "text is longer than " + strconv.Itoa(opts.MaxLength) + " bytes"}
+	}
+
+	if opts.Strict {
+		var quoted = strconv.QuoteRune(rune(sep))
+		if sep == '/' {
+			if len(s) == 0 || s[0] != sep {
+				return HierarchyId{}, &ParseError{Input: s, Pos: 0, Msg: "must start with " + quoted}
+			}
+			if s[len(s)-1] != sep {
+				return HierarchyId{}, &ParseError{Input: s, Pos: len(s), Msg: "must end with " + quoted}
+			}
+		} else if len(s) > 0 && s[0] == sep {
+			return HierarchyId{}, &ParseError{Input: s, Pos: 0, Msg: "must not start with " + quoted}
+		} else if len(s) > 0 && s[len(s)-1] == sep {
+			return HierarchyId{}, &ParseError{Input: s, Pos: len(s) - 1, Msg: "must not end with " + quoted}
+		}
+	}
+
+	var body = s
+	var offset = 0
+	if sep == '/' && opts.Strict {
+		// The root '/' has no levels, otherwise remove the leading and trailing separators
+		if len(s) == 1 {
+			return HierarchyId{Data: []int64{}}, nil
+		}
+		body = s[1 : len(s)-1]
+		offset = 1
+	}
+
+	// In strict mode only '/' is the root, '//' is an empty level
+	var levels = []int64{}
+	if body == "" && (sep != '/' || !opts.Strict) {
+		return HierarchyId{Data: levels}, nil
+	}
+
+	for _, part := range strings.Split(body, string(sep)) {
+		var pos = offset
+		offset += len(part) + 1
+
+		if part == "" {
+			if opts.Strict {
+				return HierarchyId{}, &ParseError{Input: s, Pos: pos, Msg: "empty level"}
+			}
+			continue
+		}
+
+		var msg = checkLevel(part, opts)
+		if msg != "" {
+			return HierarchyId{}, &ParseError{Input: s, Pos: pos, Msg: msg}
+		}
+
+		var level, perr = strconv.ParseInt(part, 10, 64)
+		if perr != nil {
+			return HierarchyId{}, &ParseError{Input: s, Pos: pos, Msg: "invalid level " + strconv.Quote(part), Err: perr}
+		}
+
+		if opts.MaxDepth > 0 && len(levels) >= opts.MaxDepth {
+			return HierarchyId{}, &ParseError{Input: s, Pos: pos, Msg: "more than " + strconv.Itoa(opts.MaxDepth) + " levels"}
+		}
+
+		levels = append(levels, level)
+	}
+
+	return HierarchyId{Data: levels}, nil
+}
+
+// Check the format of a level in strict mode, returns a description of the problem found (if any).
+func checkLevel(part string, opts ParseOptions) string {
+	if !opts.Strict {
+		return ""
+	}
+
+	if part[0] == '+' {
+		return "level " + strconv.Quote(part) + " must not have a '+' sign"
+	}
+
+	var digits = strings.TrimPrefix(part, "-")
+	if digits != part && digits != "" && strings.Trim(digits, "0") == "" {
+		return "level " + strconv.Quote(part) + " must not be negative zero"
+	}
+
+	if len(digits) > 1 && digits[0] == '0' && !opts.AllowZeroPadding {
+		return "level " + strconv.Quote(part) + " must not have leading zeros"
+	}
+
+	return ""
+}
+
+// Parse a hierarchyid and return its canonical text representation (e.g. '1.02.3' becomes '/1/2/3/').
+func Canonicalize(s string, opts ParseOptions) (string, error) {
+	var h, err = Parse(s, opts)
+	if err != nil {
+		return "", err
+	}
+
+	return ToString(h.Data), nil
+}
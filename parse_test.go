@@ -0,0 +1,83 @@
+package hierarchyid
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+type TestParseStruct struct {
+	input  string
+	opts   ParseOptions
+	output []int64
+	pos    int
+}
+
+var TestParseStrict = ParseOptions{Strict: true}
+
+var TestParseData []TestParseStruct = []TestParseStruct{
+	{"/", TestParseStrict, []int64{}, -1},
+	{"/1/2/", TestParseStrict, []int64{1, 2}, -1},
+	{"/-1/0/754/", TestParseStrict, []int64{-1, 0, 754}, -1},
+	{"1/2/", TestParseStrict, nil, 0},
+	{"/1/2", TestParseStrict, nil, 4},
+	{"", TestParseStrict, nil, 0},
+	{"/1//2/", TestParseStrict, nil, 3},
+	{"/1/+3/", TestParseStrict, nil, 3},
+	{"/1/03/", TestParseStrict, nil, 3},
+	{"/1/-03/", TestParseStrict, nil, 3},
+	{"//", TestParseStrict, nil, 1},
+	{"/1/-0/", TestParseStrict, nil, 3},
+	{"/-00/", ParseOptions{Strict: true, AllowZeroPadding: true}, nil, 1},
+	{"/1/a/", TestParseStrict, nil, 3},
+	{"/0001/0002/", ParseOptions{Strict: true, AllowZeroPadding: true}, []int64{1, 2}, -1},
+	{"1//2", ParseOptions{}, []int64{1, 2}, -1},
+	{"+3", ParseOptions{}, []int64{3}, -1},
+	{"1.2.3", ParseOptions{Separator: '.'}, []int64{1, 2, 3}, -1},
+	{"1.2.3", ParseOptions{Strict: true, Separator: '.'}, []int64{1, 2, 3}, -1},
+	{".1.2", ParseOptions{Strict: true, Separator: '.'}, nil, 0},
+	{"1.2.", ParseOptions{Strict: true, Separator: '.'}, nil, 3},
+	{"1>2>3", ParseOptions{Strict: true, Separator: '>'}, []int64{1, 2, 3}, -1},
+	{"/1/2/3/", ParseOptions{MaxDepth: 2}, nil, 5},
+	{"/1/2/", ParseOptions{MaxDepth: 2}, []int64{1, 2}, -1},
+	{"/1/2/3/", ParseOptions{MaxLength: 5}, nil, 5},
+	{"/99999999999999999999/", TestParseStrict, nil, 1},
+}
+
+func TestParse(t *testing.T) {
+	for _, d := range TestParseData {
+		h, err := Parse(d.input, d.opts)
+
+		if d.pos < 0 {
+			if err != nil || !slices.Equal(h.Data, d.output) {
+				t.Errorf("Expected %v to return %v, got %v %v", d.input, d.output, h.Data, err)
+			}
+			continue
+		}
+
+		var perr *ParseError
+		if !errors.As(err, &perr) {
+			t.Errorf("Expected %v to fail with ParseError, got %v", d.input, err)
+			continue
+		}
+
+		if perr.Pos != d.pos {
+			t.Errorf("Expected %v to fail at %v, got %v (%v)", d.input, d.pos, perr.Pos, perr)
+		}
+	}
+}
+
+func TestParseErrorUnwrap(t *testing.T) {
+	_, err := Parse("/99999999999999999999/", ParseOptions{})
+	if !errors.Is(err, strconv.ErrRange) {
+		t.Errorf("Expected range error, got %v", err)
+	}
+}
+
+func TestCanonicalize(t *testing.T) {
+	result, err := Canonicalize("0001.0002.3", ParseOptions{Separator: '.'})
+	if err != nil || result != "/1/2/3/" {
+		t.Errorf("Expected /1/2/3/, got %v %v", result, err)
+	}
+}
@@ -0,0 +1,40 @@
+package hierarchyid
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Get the lowest common ancestor of all rows selected by a query in a single query.
+//
+// The query should already have the model (or table) and conditions applied, column is the name of the hierarchyid column.
+//
+// Rows sorted in depth-first order have the common ancestor of the whole set equal to the common ancestor of the first and last rows, only the MIN and MAX values are read from the database.
+func CommonAncestor(db *gorm.DB, column string) (HierarchyId, error) {
+	var col = clause.Column{Name: column}
+	var min, max HierarchyId
+
+	rows, err := db.Select("MIN(?), MAX(?)", col, col).Rows()
+	if err != nil {
+		return HierarchyId{}, err
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		err = rows.Scan(&min, &max)
+		if err != nil {
+			return HierarchyId{}, err
+		}
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return HierarchyId{}, err
+	}
+
+	if min.Data == nil || max.Data == nil {
+		return HierarchyId{}, gorm.ErrRecordNotFound
+	}
+
+	return min.LowestCommonAncestor(max), nil
+}
@@ -0,0 +1,266 @@
+package hierarchyid
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// Returned by the ScopePlugin when a write would place a row outside of the scope of the context.
+var ErrOutOfScope = errors.New("hierarchyid: row is outside of the scope of the context")
+
+// Key of the scope stored in the context.
+type scopeKey struct{}
+
+// Subtree that restricts the rows visible to a context.
+type subtreeScope struct {
+	// Name of the hierarchyid column (or field).
+	column string
+
+	// Root of the subtree.
+	node HierarchyId
+}
+
+// Create a context that restricts the rows of the models with a hierarchyid column to a subtree (the node and its descendants).
+//
+// The restriction is applied by the ScopePlugin to every query, update and delete executed with the context.
+func WithScope(ctx context.Context, column string, node HierarchyId) context.Context {
+	return context.WithValue(ctx, scopeKey{}, subtreeScope{column: column, node: node})
+}
+
+// Get the column and the subtree of the scope stored in the context.
+func ScopeFromContext(ctx context.Context) (string, HierarchyId, bool) {
+	if ctx == nil {
+		return "", HierarchyId{}, false
+	}
+
+	var scope, ok = ctx.Value(scopeKey{}).(subtreeScope)
+	return scope.column, scope.node, ok
+}
+
+// Plugin that restricts queries, updates and deletes to the subtree of the context (see WithScope), emulating row-level security.
+//
+// Models that have the hierarchyid column of the scope get an IsDescendantOf condition added. Creates and updates that would place a row outside of the subtree (or create a row without a hierarchyid) fail with ErrOutOfScope. Raw SQL (Raw and Exec) is not restricted.
+//
+//	db.Use(hierarchyid.ScopePlugin{})
+type ScopePlugin struct{}
+
+// Name implements the gorm.Plugin interface.
+func (ScopePlugin) Name() string {
+	return "hierarchyid:scope"
+}
+
+// Initialize implements the gorm.Plugin interface, registers the callbacks before the query, row, update, delete and create callbacks.
+func (ScopePlugin) Initialize(db *gorm.DB) error {
+	var callbacks = db.Callback()
+
+	var err = callbacks.Query().Before("gorm:query").Register("hierarchyid:scope", restrictRows)
+	if err != nil {
+		return err
+	}
+
+	err = callbacks.Row().Before("gorm:row").Register("hierarchyid:scope", restrictRows)
+	if err != nil {
+		return err
+	}
+
+	err = callbacks.Delete().Before("gorm:delete").Register("hierarchyid:scope", restrictRows)
+	if err != nil {
+		return err
+	}
+
+	err = callbacks.Update().Before("gorm:update").Register("hierarchyid:scope", restrictUpdate)
+	if err != nil {
+		return err
+	}
+
+	return callbacks.Create().Before("gorm:create").Register("hierarchyid:scope", restrictCreate)
+}
+
+// Get the hierarchyid field of the scope of the context (nil if there is no scope or the model does not have the column).
+func scopeField(db *gorm.DB) (*schema.Field, HierarchyId) {
+	if db.Error != nil || db.Statement.Schema == nil {
+		return nil, HierarchyId{}
+	}
+
+	var column, node, ok = ScopeFromContext(db.Statement.Context)
+	if !ok {
+		return nil, HierarchyId{}
+	}
+
+	var field = db.Statement.Schema.LookUpField(column)
+	if field == nil || field.DBName == "" || field.FieldType != reflect.TypeOf(HierarchyId{}) {
+		return nil, HierarchyId{}
+	}
+
+	return field, node
+}
+
+// Check if a hierarchyid is the node or one of its descendants.
+func inScope(h HierarchyId, node HierarchyId) bool {
+	return slices.Equal(h.Data, node.Data) || h.IsDescendantOf(node)
+}
+
+// Group the conditions of the statement so that the conditions added after them are not part of an OR (e.g. Where(a).Or(b)).
+func groupConditions(stmt *gorm.Statement) {
+	var c, ok = stmt.Clauses["WHERE"]
+	if !ok {
+		return
+	}
+
+	var where, isWhere = c.Expression.(clause.Where)
+	if !isWhere {
+		return
+	}
+
+	for _, expr := range where.Exprs {
+		if or, ok := expr.(clause.OrConditions); ok && len(or.Exprs) == 1 {
+			where.Exprs = []clause.Expression{clause.And(where.Exprs...)}
+			c.Expression = where
+			stmt.Clauses["WHERE"] = c
+			return
+		}
+	}
+}
+
+// Add the condition of the scope to the statement.
+func restrictRows(db *gorm.DB) {
+	var field, node = scopeField(db)
+	if field == nil {
+		return
+	}
+
+	groupConditions(db.Statement)
+	db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+		clause.Expr{SQL: "?.IsDescendantOf(CAST(? AS hierarchyid)) = 1", Vars: []any{clause.Column{Table: clause.CurrentTable, Name: field.DBName}, node}},
+	}})
+}
+
+// Restrict the rows updated and check that the new values of the hierarchyid stay in the scope.
+func restrictUpdate(db *gorm.DB) {
+	var field, node = scopeField(db)
+	if field == nil {
+		return
+	}
+
+	// Expressions are checked with the conditions of the statement
+	groupConditions(db.Statement)
+
+	switch dest := db.Statement.Dest.(type) {
+	case map[string]any:
+		for key, value := range dest {
+			if db.Statement.Schema.LookUpField(key) == field {
+				checkScope(db, node, value)
+			}
+		}
+	default:
+		var value = reflect.Indirect(reflect.ValueOf(dest))
+		if value.IsValid() && value.Type() == db.Statement.Schema.ModelType {
+			var h, zero = field.ValueOf(db.Statement.Context, value)
+
+			// Same rule used by gorm to write a field, zero values are only written when the field is selected (e.g. Save)
+			var selected, restricted = db.Statement.SelectAndOmitColumns(false, true)
+			var v, ok = selected[field.DBName]
+			if (ok && v) || (!ok && !restricted && !zero) {
+				checkScope(db, node, h)
+			}
+		}
+	}
+
+	restrictRows(db)
+}
+
+// Check that the rows created are in the scope.
+func restrictCreate(db *gorm.DB) {
+	var field, node = scopeField(db)
+	if field == nil {
+		return
+	}
+
+	switch dest := db.Statement.Dest.(type) {
+	case map[string]any:
+		checkCreateMap(db, field, node, dest)
+	case *map[string]any:
+		checkCreateMap(db, field, node, *dest)
+	case []map[string]any:
+		for _, row := range dest {
+			checkCreateMap(db, field, node, row)
+		}
+	case *[]map[string]any:
+		for _, row := range *dest {
+			checkCreateMap(db, field, node, row)
+		}
+	default:
+		var rows = db.Statement.ReflectValue
+		switch rows.Kind() {
+		case reflect.Slice, reflect.Array:
+			for i := 0; i < rows.Len(); i++ {
+				var h, _ = field.ValueOf(db.Statement.Context, reflect.Indirect(rows.Index(i)))
+				checkScope(db, node, h)
+			}
+		case reflect.Struct:
+			var h, _ = field.ValueOf(db.Statement.Context, rows)
+			checkScope(db, node, h)
+		}
+	}
+}
+
+// Check that a row created from a map is in the scope, rows without the hierarchyid would be outside of it.
+func checkCreateMap(db *gorm.DB, field *schema.Field, node HierarchyId, row map[string]any) {
+	for key, value := range row {
+		if db.Statement.Schema.LookUpField(key) == field {
+			checkScope(db, node, value)
+			return
+		}
+	}
+
+	_ = db.AddError(ErrOutOfScope)
+}
+
+// Check that a new value of the hierarchyid is in the scope.
+//
+// Null values are outside of the scope. Text and binary values are read as the database would convert them, expressions (e.g. GetReparentedValue) are evaluated in the database for the rows selected by the conditions of the statement. Values of other types are rejected.
+func checkScope(db *gorm.DB, node HierarchyId, value any) {
+	switch v := value.(type) {
+	case HierarchyId:
+		if v.Data == nil || !inScope(v, node) {
+			_ = db.AddError(ErrOutOfScope)
+		}
+	case *HierarchyId:
+		if v == nil {
+			_ = db.AddError(ErrOutOfScope)
+			return
+		}
+		checkScope(db, node, *v)
+	case string, []byte:
+		var h HierarchyId
+		if h.Scan(v) != nil {
+			_ = db.AddError(ErrOutOfScope)
+			return
+		}
+		checkScope(db, node, h)
+	case clause.Expression:
+		var tx = db.Session(&gorm.Session{NewDB: true}).Model(db.Statement.Model)
+		if db.Statement.Unscoped {
+			tx = tx.Unscoped()
+		}
+		if where, ok := db.Statement.Clauses["WHERE"].Expression.(clause.Where); ok {
+			tx.Statement.AddClause(where)
+		}
+
+		var count int64
+		var err = tx.Where("ISNULL((?).IsDescendantOf(CAST(? AS hierarchyid)), 0) = 0", v, node).Count(&count).Error
+		if err != nil {
+			_ = db.AddError(err)
+		} else if count > 0 {
+			_ = db.AddError(ErrOutOfScope)
+		}
+	default:
+		_ = db.AddError(ErrOutOfScope)
+	}
+}
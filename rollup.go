@@ -0,0 +1,127 @@
+package hierarchyid
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Aggregate function used by Rollup.
+type Aggregate int
+
+const (
+	// Number of rows with a non-null value (all rows if the column is empty).
+	AggregateCount Aggregate = iota
+
+	// Sum of the values.
+	AggregateSum
+
+	// Smallest value.
+	AggregateMin
+
+	// Largest value.
+	AggregateMax
+)
+
+// Options used to select the nodes aggregated by Rollup.
+type RollupOptions struct {
+	// Nodes are selected below this node (root if not set).
+	Parent HierarchyId
+
+	// Number of levels below the parent of the nodes selected, 1 (direct children) if not set.
+	Depth int
+}
+
+// Aggregated value of a node and all of its descendants.
+type RollupResult[V any] struct {
+	// Node aggregated.
+	Node HierarchyId
+
+	// Result of the aggregate (zero value if null).
+	Value V
+}
+
+// Aggregate a column over each node and all of its descendants (e.g. total budget of each department including sub-departments).
+//
+// The nodes are selected with the options provided, a single query joining the table with itself on IsDescendantOf is used. Soft deleted rows are ignored. Results are sorted in depth-first order.
+func Rollup[V any](ctx context.Context, db *gorm.DB, model any, column string, agg Aggregate, opts RollupOptions) ([]RollupResult[V], error) {
+	var t, err = newTree(ctx, db, model)
+	if err != nil {
+		return nil, err
+	}
+
+	var function string
+	switch agg {
+	case AggregateCount:
+		function = "COUNT"
+	case AggregateSum:
+		function = "SUM"
+	case AggregateMin:
+		function = "MIN"
+	case AggregateMax:
+		function = "MAX"
+	default:
+		return nil, errors.New("hierarchyid: unknown aggregate")
+	}
+
+	var parent = opts.Parent
+	if parent.Data == nil {
+		parent = GetRoot()
+	}
+
+	var depth = opts.Depth
+	if depth <= 0 {
+		depth = 1
+	}
+
+	var node = clause.Column{Table: "node", Name: t.path.DBName}
+	var descendant = clause.Column{Table: "descendant", Name: t.path.DBName}
+
+	var value any = clause.Expr{SQL: "*"}
+	if column != "" {
+		value = clause.Column{Table: "descendant", Name: column}
+	}
+
+	var query = t.db.Table("? AS node", clause.Table{Name: t.schema.Table}).
+		Select("?, "+function+"(?)", node, value).
+		Joins("JOIN ? AS descendant ON ?.IsDescendantOf(?) = 1", clause.Table{Name: t.schema.Table}, descendant, node).
+		Where("?.IsDescendantOf(CAST(? AS hierarchyid)) = 1", node, parent).
+		Where("?.GetLevel() = ?", node, parent.GetLevel()+depth).
+		Clauses(clause.GroupBy{Columns: []clause.Column{node}}).
+		Order(clause.OrderByColumn{Column: node})
+
+	query = t.inTree(t.inTree(query, "node"), "descendant")
+
+	if deletedAt := t.deletedAt(); deletedAt != nil {
+		query = query.
+			Where("? IS NULL", clause.Column{Table: "node", Name: deletedAt.DBName}).
+			Where("? IS NULL", clause.Column{Table: "descendant", Name: deletedAt.DBName})
+	}
+
+	rows, err := query.Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results = []RollupResult[V]{}
+	for rows.Next() {
+		var result RollupResult[V]
+		var value *V
+
+		err = rows.Scan(&result.Node, &value)
+		if err != nil {
+			return nil, err
+		}
+
+		if value != nil {
+			result.Value = *value
+		}
+
+		results = append(results, result)
+	}
+
+	return results, rows.Err()
+}
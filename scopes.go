@@ -0,0 +1,96 @@
+package hierarchyid
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Scope that selects a node and all of its descendants.
+//
+// The hierarchyid column is found from the model of the query, e.g. db.Scopes(hierarchyid.DescendantsOf(node)).Find(&rows).
+func DescendantsOf(node HierarchyId) func(*gorm.DB) *gorm.DB {
+	return treeScope(func(tx *gorm.DB, t *tree) *gorm.DB {
+		return tx.Where("?.IsDescendantOf(CAST(? AS hierarchyid)) = 1", t.column(), node)
+	})
+}
+
+// Scope that selects a node and its descendants up to a number of levels below it (e.g. 1 for the node and its children).
+func Subtree(node HierarchyId, maxDepth int) func(*gorm.DB) *gorm.DB {
+	return treeScope(func(tx *gorm.DB, t *tree) *gorm.DB {
+		return tx.Where("?.IsDescendantOf(CAST(? AS hierarchyid)) = 1", t.column(), node).
+			Where("?.GetLevel() <= ?", t.column(), node.GetLevel()+maxDepth)
+	})
+}
+
+// Scope for keyset pagination in depth-first order, selects the rows after the cursor sorted by the hierarchyid.
+//
+// Use with Limit to read a page, the hierarchyid of the last row is the cursor of the next page. A cursor without data (e.g. from ParseCursor("")) starts from the first row.
+func After(cursor HierarchyId) func(*gorm.DB) *gorm.DB {
+	return treeScope(func(tx *gorm.DB, t *tree) *gorm.DB {
+		if cursor.Data != nil {
+			tx = tx.Where("? > CAST(? AS hierarchyid)", t.column(), cursor)
+		}
+
+		return tx.Order(clause.OrderByColumn{Column: t.column()})
+	})
+}
+
+// Scope that selects the direct children of a node.
+func ChildrenOf(node HierarchyId) func(*gorm.DB) *gorm.DB {
+	return treeScope(func(tx *gorm.DB, t *tree) *gorm.DB {
+		return tx.Where("?.GetAncestor(1) = CAST(? AS hierarchyid)", t.column(), node)
+	})
+}
+
+// Scope that selects the ancestors of a node including a row at the root '/' (the node itself is not included).
+func AncestorsOf(node HierarchyId) func(*gorm.DB) *gorm.DB {
+	var levels = lineage(node)
+
+	return treeScope(func(tx *gorm.DB, t *tree) *gorm.DB {
+		return tx.Where("? IN ?", t.column(), levels[:len(levels)-1])
+	})
+}
+
+// Scope that hides rows that are soft deleted or have a soft deleted ancestor.
+//
+// Soft deleting a node does not change its descendants, with this scope they are also hidden. Requires a gorm.DeletedAt field in the model.
+func WithoutDeletedAncestors() func(*gorm.DB) *gorm.DB {
+	return treeScope(func(tx *gorm.DB, t *tree) *gorm.DB {
+		var deletedAt = t.deletedAt()
+		if deletedAt == nil {
+			_ = tx.AddError(ErrNoSoftDelete)
+			return tx
+		}
+
+		var deleted = t.inTree(t.db.Session(&gorm.Session{NewDB: true}), "deleted").
+			Table("? AS deleted", clause.Table{Name: t.schema.Table}).
+			Select("1").
+			Where("? IS NOT NULL", clause.Column{Table: "deleted", Name: deletedAt.DBName}).
+			Where("?.IsDescendantOf(?) = 1", clause.Column{Table: t.schema.Table, Name: t.path.DBName}, clause.Column{Table: "deleted", Name: t.path.DBName})
+
+		return tx.Where("NOT EXISTS (?)", deleted)
+	})
+}
+
+// Create a scope that uses the tree of the model (or destination) of the query, the query is restricted to the tree of the context.
+func treeScope(scope func(tx *gorm.DB, t *tree) *gorm.DB) func(*gorm.DB) *gorm.DB {
+	return func(tx *gorm.DB) *gorm.DB {
+		var model = tx.Statement.Model
+		if model == nil {
+			model = tx.Statement.Dest
+		}
+
+		var t, err = newTree(tx.Statement.Context, tx, model)
+		if err != nil {
+			_ = tx.AddError(err)
+			return tx
+		}
+
+		// The tree condition is only added once when multiple scopes are used
+		if _, ok := tx.InstanceGet("hierarchyid:tree"); !ok {
+			tx = t.inTree(tx, clause.CurrentTable).InstanceSet("hierarchyid:tree", true)
+		}
+
+		return scope(tx, t)
+	}
+}
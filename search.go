@@ -0,0 +1,56 @@
+package hierarchyid
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Options used by SearchTree.
+type SearchOptions struct {
+	// Also include the descendants of the rows that match.
+	Descendants bool
+}
+
+// Search the rows that match a condition (e.g. gorm.Expr("name LIKE ?", "%de%")) and include all of their ancestors, so that the result is still a connected tree.
+//
+// A single query is used, each row is checked for a match in its subtree (or in its ancestors for descendants). Results are sorted in depth-first order.
+func SearchTree[T any](ctx context.Context, db *gorm.DB, model *T, where clause.Expression, opts SearchOptions) ([]T, error) {
+	var t, err = newTree(ctx, db, model)
+	if err != nil {
+		return nil, err
+	}
+
+	var row = clause.Column{Table: t.schema.Table, Name: t.path.DBName}
+	var match = clause.Column{Table: "found", Name: t.path.DBName}
+
+	var related = t.db.Session(&gorm.Session{NewDB: true}).
+		Table("? AS found", clause.Table{Name: t.schema.Table}).
+		Select("1").
+		Where(where)
+
+	if opts.Descendants {
+		related = related.Where("(?.IsDescendantOf(?) = 1 OR ?.IsDescendantOf(?) = 1)", match, row, row, match)
+	} else {
+		related = related.Where("?.IsDescendantOf(?) = 1", match, row)
+	}
+
+	related = t.inTree(related, "found")
+
+	if deletedAt := t.deletedAt(); deletedAt != nil {
+		related = related.Where("? IS NULL", clause.Column{Table: "found", Name: deletedAt.DBName})
+	}
+
+	var rows = []T{}
+
+	err = t.query(t.db).
+		Where("EXISTS (?)", related).
+		Order(clause.OrderByColumn{Column: t.column()}).
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,192 @@
+package hierarchyid
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Returned when a soft delete is requested on a model without a gorm.DeletedAt field.
+var ErrNoSoftDelete = errors.New("hierarchyid: model has no gorm.DeletedAt field")
+
+// Policy used to delete a node from the tree.
+type DeletePolicy int
+
+const (
+	// Permanently delete the node and all of its descendants (including soft deleted rows).
+	DeleteHard DeletePolicy = iota
+
+	// Soft delete the node and all of its descendants, requires a gorm.DeletedAt field in the model.
+	DeleteSoft
+
+	// Delete only the node (soft delete if supported by the model), its children are promoted to the parent of the node.
+	//
+	// The children are placed right after the node keeping their order, their subtrees are moved with them.
+	DeletePromote
+)
+
+// Copy a node and all of its descendants to a new child of the target parent.
+//
+// New rows are inserted with the keys moved below the new node and the primary keys and the autoCreateTime/autoUpdateTime fields cleared (so new ids and timestamps are generated), the mutate function (optional) can change the fields of each copy before it is inserted. Rows are read and inserted in batches of BatchSize in a single transaction.
+//
+// Returns the hierarchyid of the copy of the source node, gorm.ErrRecordNotFound if the source node does not exist.
+func CopySubtree[T any](ctx context.Context, db *gorm.DB, model *T, source HierarchyId, targetParent HierarchyId, mutate func(*T)) (HierarchyId, error) {
+	if slices.Equal(targetParent.Data, source.Data) || targetParent.IsDescendantOf(source) {
+		return HierarchyId{}, ErrInvalidMove
+	}
+
+	var t, err = newTree(ctx, db, model)
+	if err != nil {
+		return HierarchyId{}, err
+	}
+
+	var root HierarchyId
+
+	err = t.db.Transaction(func(tx *gorm.DB) error {
+		var values, err = t.childValues(tx, targetParent)
+		if err != nil {
+			return err
+		}
+
+		value, err := DenseAllocator{}.Next(values)
+		if err != nil {
+			return err
+		}
+		root = targetParent.Child(value)
+
+		var rows = []T{}
+		var copied = 0
+
+		err = t.query(tx).
+			Where("?.IsDescendantOf(CAST(? AS hierarchyid)) = 1", t.column(), source).
+			FindInBatches(&rows, BatchSize, func(batch *gorm.DB, n int) error {
+				// Rows are copied, the batch keeps the original primary keys used to read the next page
+				var copies = make([]T, len(rows))
+				copy(copies, rows)
+
+				for i := range copies {
+					var row = &copies[i]
+
+					var path = t.getPath(row)
+					var suffix, _ = path.StripPrefix(source)
+					err := t.setPath(row, root.Join(suffix))
+					if err != nil {
+						return err
+					}
+
+					err = t.clearGenerated(row)
+					if err != nil {
+						return err
+					}
+
+					if mutate != nil {
+						mutate(row)
+					}
+				}
+
+				copied += len(copies)
+				return tx.Omit(clause.Associations).CreateInBatches(&copies, BatchSize).Error
+			}).Error
+		if err != nil {
+			return err
+		}
+
+		if copied == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return nil
+	})
+	if err != nil {
+		return HierarchyId{}, err
+	}
+
+	return root, nil
+}
+
+// Delete a node from the tree following the policy provided, all changes are applied in a single transaction.
+func DeleteSubtree(ctx context.Context, db *gorm.DB, model any, node HierarchyId, policy DeletePolicy) error {
+	var t, err = newTree(ctx, db, model)
+	if err != nil {
+		return err
+	}
+
+	return t.db.Transaction(func(tx *gorm.DB) error {
+		var subtree = clause.Expr{SQL: "?.IsDescendantOf(CAST(? AS hierarchyid)) = 1", Vars: []any{t.column(), node}}
+
+		switch policy {
+		case DeleteHard:
+			return t.query(tx).Unscoped().Where(subtree).Delete(t.model).Error
+		case DeleteSoft:
+			if t.deletedAt() == nil {
+				return ErrNoSoftDelete
+			}
+			return t.query(tx).Where(subtree).Delete(t.model).Error
+		case DeletePromote:
+			return t.promoteChildren(tx, node)
+		default:
+			return errors.New("hierarchyid: unknown delete policy")
+		}
+	})
+}
+
+// Move the children of a node right after it (as siblings) and delete the node.
+func (t *tree) promoteChildren(tx *gorm.DB, node HierarchyId) error {
+	if node.GetLevel() == 0 {
+		return errors.New("hierarchyid: the root cannot be deleted with DeletePromote")
+	}
+
+	var children = []HierarchyId{}
+
+	err := t.query(tx).Unscoped().
+		Where("?.GetAncestor(1) = CAST(? AS hierarchyid)", t.column(), node).
+		Order(clause.OrderByColumn{Column: t.column()}).
+		Pluck(t.path.DBName, &children).Error
+	if err != nil {
+		return err
+	}
+
+	var previous = node
+	for _, child := range children {
+		var key, _, err = t.keyNextTo(tx, previous, true)
+		if err != nil {
+			return err
+		}
+
+		err = t.moveSubtree(tx, child, key)
+		if err != nil {
+			return err
+		}
+
+		previous = key
+	}
+
+	return t.query(tx).Where("? = CAST(? AS hierarchyid)", t.column(), node).Delete(t.model).Error
+}
+
+// Restore a soft deleted node and the descendants that were deleted with it.
+//
+// Rows deleted in the same operation share the same deletion time which is used as marker, descendants deleted separately before stay deleted. Nothing is changed if the node is not deleted.
+func RestoreSubtree(ctx context.Context, db *gorm.DB, model any, node HierarchyId) error {
+	var t, err = newTree(ctx, db, model)
+	if err != nil {
+		return err
+	}
+
+	var deletedAt = t.deletedAt()
+	if deletedAt == nil {
+		return ErrNoSoftDelete
+	}
+
+	var marker = t.query(t.db.Session(&gorm.Session{NewDB: true})).Unscoped().
+		Select(deletedAt.DBName).
+		Where("? = CAST(? AS hierarchyid)", t.column(), node)
+
+	return t.query(t.db).Unscoped().
+		Where("?.IsDescendantOf(CAST(? AS hierarchyid)) = 1", t.column(), node).
+		Where("? = (?)", clause.Column{Name: deletedAt.DBName}, marker).
+		Update(deletedAt.DBName, nil).Error
+}
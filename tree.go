@@ -0,0 +1,206 @@
+package hierarchyid
+
+import (
+	"context"
+	"errors"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// Number of rows read or written per batch by the tree helpers.
+var BatchSize = 500
+
+// Returned when the model used by a tree helper has no hierarchyid field.
+var ErrNoPathField = errors.New("hierarchyid: model has no HierarchyId field")
+
+// Hierarchy stored in the table of a model, used by the tree helpers to build queries.
+//
+// The hierarchyid column is the field tagged with `hierarchyid:"path"` or the first field of type HierarchyId.
+type tree struct {
+	// Database session with the context applied.
+	db *gorm.DB
+
+	// Model used to select the table.
+	model any
+
+	// Parsed schema of the model.
+	schema *schema.Schema
+
+	// Field that stores the hierarchyid.
+	path *schema.Field
+
+	// Field tagged with `hierarchyid:"tree"` that identifies the tree of each row (nil if the table has a single tree).
+	treeField *schema.Field
+
+	// Identifier of the tree used by the queries (from WithTree).
+	treeId any
+}
+
+// Parse the model and find the hierarchyid field.
+func newTree(ctx context.Context, db *gorm.DB, model any) (*tree, error) {
+	var stmt = &gorm.Statement{DB: db}
+
+	err := stmt.Parse(model)
+	if err != nil {
+		return nil, err
+	}
+
+	return schemaTree(ctx, db, model, stmt.Schema)
+}
+
+// Create the tree of a parsed schema, the tree id is read from the context when the model has a tree field.
+func schemaTree(ctx context.Context, db *gorm.DB, model any, s *schema.Schema) (*tree, error) {
+	var t, err = baseTree(ctx, db, model, s)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.treeField != nil {
+		var id, ok = TreeFromContext(ctx)
+		if !ok {
+			return nil, ErrNoTreeId
+		}
+		t.treeId = id
+	}
+
+	return t, nil
+}
+
+// Create the tree of a parsed schema without a tree id.
+func baseTree(ctx context.Context, db *gorm.DB, model any, s *schema.Schema) (*tree, error) {
+	var path = pathField(s)
+	if path == nil {
+		return nil, ErrNoPathField
+	}
+
+	var t = &tree{db: db.WithContext(ctx), model: model, schema: s, path: path}
+
+	for _, field := range s.Fields {
+		if field.DBName != "" && field.Tag.Get("hierarchyid") == "tree" {
+			t.treeField = field
+			break
+		}
+	}
+
+	return t, nil
+}
+
+// Find the field tagged with `hierarchyid:"path"` or the first field of type HierarchyId (nil if not found).
+func pathField(s *schema.Schema) *schema.Field {
+	var path *schema.Field
+	for _, field := range s.Fields {
+		if field.DBName == "" || field.FieldType != reflect.TypeOf(HierarchyId{}) {
+			continue
+		}
+
+		if field.Tag.Get("hierarchyid") == "path" {
+			return field
+		}
+
+		if path == nil {
+			path = field
+		}
+	}
+
+	return path
+}
+
+// Column that stores the hierarchyid.
+func (t *tree) column() clause.Column {
+	return clause.Column{Name: t.path.DBName}
+}
+
+// Start a query on the table of the model, restricted to the tree of the context.
+func (t *tree) query(tx *gorm.DB) *gorm.DB {
+	return t.inTree(tx.Model(t.model), "")
+}
+
+// Restrict a query to the tree of the context, table is the name (or alias) used to qualify the tree column.
+func (t *tree) inTree(tx *gorm.DB, table string) *gorm.DB {
+	if t.treeField == nil {
+		return tx
+	}
+
+	return tx.Where(clause.Eq{Column: clause.Column{Table: table, Name: t.treeField.DBName}, Value: t.treeId})
+}
+
+// Set the tree id of a row (if the model has a tree field).
+func (t *tree) setTree(row any) error {
+	if t.treeField == nil {
+		return nil
+	}
+
+	return t.treeField.Set(t.db.Statement.Context, reflect.Indirect(reflect.ValueOf(row)), t.treeId)
+}
+
+// Get the level values used by the children of a node, including soft deleted rows that still hold their key.
+func (t *tree) childValues(tx *gorm.DB, parent HierarchyId) ([]int64, error) {
+	var children = []HierarchyId{}
+
+	err := t.query(tx).Unscoped().
+		Where("?.GetAncestor(1) = CAST(? AS hierarchyid)", t.column(), parent).
+		Pluck(t.path.DBName, &children).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var values = make([]int64, 0, len(children))
+	for _, c := range children {
+		if len(c.Data) > 0 {
+			values = append(values, c.Data[len(c.Data)-1])
+		}
+	}
+
+	return values, nil
+}
+
+// Set the hierarchyid of a row.
+func (t *tree) setPath(row any, h HierarchyId) error {
+	return t.path.Set(t.db.Statement.Context, reflect.Indirect(reflect.ValueOf(row)), h)
+}
+
+// Get the hierarchyid of a row.
+func (t *tree) getPath(row any) HierarchyId {
+	var value, _ = t.path.ValueOf(t.db.Statement.Context, reflect.Indirect(reflect.ValueOf(row)))
+	var h, _ = value.(HierarchyId)
+	return h
+}
+
+// Clear the primary key and the autoCreateTime/autoUpdateTime fields of a row so that new values are generated on insert.
+func (t *tree) clearGenerated(row any) error {
+	var value = reflect.Indirect(reflect.ValueOf(row))
+
+	for _, field := range t.schema.Fields {
+		if !field.PrimaryKey && field.AutoCreateTime == 0 && field.AutoUpdateTime == 0 {
+			continue
+		}
+
+		err := field.Set(t.db.Statement.Context, value, reflect.Zero(field.FieldType).Interface())
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Move a node and all of its descendants (including soft deleted rows) to a new position.
+func (t *tree) moveSubtree(tx *gorm.DB, from HierarchyId, to HierarchyId) error {
+	return t.query(tx).Unscoped().
+		Where("?.IsDescendantOf(CAST(? AS hierarchyid)) = 1", t.column(), from).
+		Update(t.path.DBName, gorm.Expr("?.GetReparentedValue(CAST(? AS hierarchyid), CAST(? AS hierarchyid))", t.column(), from, to)).Error
+}
+
+// Get the gorm.DeletedAt field used for soft delete (nil if the model does not support soft delete).
+func (t *tree) deletedAt() *schema.Field {
+	for _, field := range t.schema.Fields {
+		if field.DBName != "" && field.FieldType == reflect.TypeOf(gorm.DeletedAt{}) {
+			return field
+		}
+	}
+
+	return nil
+}
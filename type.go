@@ -1,6 +1,7 @@
 package hierarchyid
 
 import (
+	"encoding/hex"
 	"errors"
 	"strconv"
 	"strings"
@@ -40,11 +41,14 @@ func ToString(data HierarchyIdData) string {
 }
 
 // Get all ancestors (parents) of a hierarchyid.
+//
+// Each ancestor is a new slice, changes to the result do not affect the input.
 func GetAncestors(data HierarchyIdData) []HierarchyIdData {
 	var parents []HierarchyIdData = []HierarchyIdData{}
 
 	for i := 0; i < len(data)-1; i++ {
-		var parent = data[0 : i+1]
+		var parent = make([]int64, i+1)
+		copy(parent, data)
 		parents = append(parents, parent)
 	}
 
@@ -52,12 +56,117 @@ func GetAncestors(data HierarchyIdData) []HierarchyIdData {
 }
 
 // Get the direct ancestor of a hierarchyid.
+//
+// The ancestor is a new slice, changes to the result do not affect the input.
 func GetAncestor(data HierarchyIdData) HierarchyIdData {
 	if len(data) == 0 {
 		return []int64{}
 	}
 
-	return data[0 : len(data)-1]
+	var parent = make([]int64, len(data)-1)
+	copy(parent, data)
+	return parent
+}
+
+// Get the lowest common ancestor of a set of hierarchyids.
+//
+// The deepest hierarchyid that is an ancestor (or equal) of all the hierarchyids provided, e.g. '/1/2/3/' and '/1/2/5/1/' have '/1/2/' as common ancestor.
+func LowestCommonAncestor(data ...HierarchyIdData) HierarchyIdData {
+	if len(data) == 0 {
+		return []int64{}
+	}
+
+	var length = len(data[0])
+	for _, d := range data[1:] {
+		var i = 0
+		for i < length && i < len(d) && d[i] == data[0][i] {
+			i++
+		}
+		length = i
+	}
+
+	var ancestor = make([]int64, length)
+	copy(ancestor, data[0])
+	return ancestor
+}
+
+// Get the number of edges in the tree between two hierarchyids.
+func Distance(a HierarchyIdData, b HierarchyIdData) int {
+	var common = len(LowestCommonAncestor(a, b))
+	return len(a) + len(b) - 2*common
+}
+
+// Get the relative path from a hierarchyid to another (e.g. '/1/2/' to '/1/3/1/' is '../3/1/').
+//
+// The path is formed by '..' to go up a level and the values of the levels to go down, './' when both hierarchyids are the same.
+func Relative(from HierarchyIdData, to HierarchyIdData) string {
+	var common = len(LowestCommonAncestor(from, to))
+
+	var r = strings.Repeat("../", len(from)-common)
+	for _, level := range to[common:] {
+		r += strconv.FormatInt(level, 10) + "/"
+	}
+
+	if r == "" {
+		return "./"
+	}
+
+	return r
+}
+
+// Resolve a relative path (e.g. '../3/1/') from a base hierarchyid.
+//
+// Paths starting with '/' are absolute and resolved from the root.
+func Resolve(base HierarchyIdData, rel string) (HierarchyIdData, error) {
+	var levels = make([]int64, len(base))
+	copy(levels, base)
+
+	if strings.HasPrefix(rel, "/") {
+		levels = []int64{}
+	}
+
+	for _, part := range strings.Split(rel, "/") {
+		switch part {
+		case "", ".":
+			continue
+		case "..":
+			if len(levels) == 0 {
+				return nil, errors.New("Relative path " + rel + " goes above the root")
+			}
+			levels = levels[:len(levels)-1]
+		default:
+			var level, err = strconv.ParseInt(part, 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			levels = append(levels, level)
+		}
+	}
+
+	return levels, nil
+}
+
+// Remove the levels of an ancestor from the start of a hierarchyid.
+//
+// E.g. '/1/2/3/4/' without '/1/2/' is '/3/4/'. Returns false if the ancestor is not an ancestor (or equal) of the hierarchyid.
+func StripPrefix(data HierarchyIdData, ancestor HierarchyIdData) (HierarchyIdData, bool) {
+	if len(ancestor) > len(data) || Compare(data[:len(ancestor)], ancestor) != 0 {
+		return nil, false
+	}
+
+	var suffix = make([]int64, len(data)-len(ancestor))
+	copy(suffix, data[len(ancestor):])
+	return suffix, true
+}
+
+// Append the levels of a suffix to a parent hierarchyid.
+//
+// E.g. '/1/2/' joined with '/3/4/' is '/1/2/3/4/'.
+func Join(parent HierarchyIdData, suffix HierarchyIdData) HierarchyIdData {
+	var data = make([]int64, 0, len(parent)+len(suffix))
+	data = append(data, parent...)
+	data = append(data, suffix...)
+	return data
 }
 
 // Create a hierarchyid data type from a string representation
@@ -85,9 +194,37 @@ func FromString(data string) (HierarchyIdData, error) {
 	return levels, nil
 }
 
+// Create a hierarchyid data type from a SQL Server hex literal (e.g. '0x5AC0').
+//
+// The '0x' prefix is optional, the root is represented by '0x' (no data).
+func FromHex(data string) (HierarchyIdData, error) {
+	if len(data) >= 2 && data[0] == '0' && (data[1] == 'x' || data[1] == 'X') {
+		data = data[2:]
+	}
+
+	var bytes, err = hex.DecodeString(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return Decode(bytes)
+}
+
+// Create a SQL Server hex literal (e.g. '0x5AC0') from the hierarchyid data type.
+func ToHex(data HierarchyIdData) (string, error) {
+	var bytes, err = Encode(data)
+	if err != nil {
+		return "", err
+	}
+
+	return "0x" + strings.ToUpper(hex.EncodeToString(bytes)), nil
+}
+
 // Compare two hierarchyid data types
 //
 // The comparison is done by comparing each level of the hierarchyid.  If the levels are the same, the next level is compared.  If the levels are different, the comparison stops and the result is returned.
+//
+// When one of the hierarchyids is an ancestor of the other 0 is returned, use CompareDepthFirst to sort hierarchyids.
 func Compare(a HierarchyIdData, b HierarchyIdData) int {
 	for i := 0; i < len(a) && i < len(b); i++ {
 		if a[i] < b[i] {
@@ -100,6 +237,24 @@ func Compare(a HierarchyIdData, b HierarchyIdData) int {
 	return 0
 }
 
+// Compare two hierarchyid data types in depth-first order.
+//
+// Same as Compare, but when one of the hierarchyids is an ancestor of the other the ancestor comes first, matching the order used by SQL Server.
+func CompareDepthFirst(a HierarchyIdData, b HierarchyIdData) int {
+	var result = Compare(a, b)
+	if result != 0 {
+		return result
+	}
+
+	if len(a) < len(b) {
+		return -1
+	} else if len(a) > len(b) {
+		return 1
+	}
+
+	return 0
+}
+
 // Decode takes a byte slice of data stored in SQL Server hierarchyid format and returns a HierarchyId.
 //
 // SQL server uses a custom binary format for hierarchyid.
@@ -148,19 +303,12 @@ func Encode(levels HierarchyIdData) ([]byte, error) {
 
 	var bin string = ""
 
-	for _, level := range levels {
+	for l, level := range levels {
 
 		// Find pattern that fits the binary data
-		var pattern *HierarchyIdPattern = nil
-		for i := 0; i < len(Patterns); i++ {
-			if Patterns[i].Min <= level && Patterns[i].Max >= level {
-				pattern = &Patterns[i]
-				break
-			}
-		}
-
+		var pattern = findPattern(level)
 		if pattern == nil {
-			return nil, errors.New("No pattern found for " + strconv.FormatInt(level, 10))
+			return nil, &RangeError{Level: l, Value: level}
 		}
 
 		// Count the number of bits in the pattern
@@ -212,6 +360,17 @@ func Encode(levels HierarchyIdData) ([]byte, error) {
 	return binBytes, nil
 }
 
+// Find the pattern used to encode a level value (nil if the value is out of range).
+func findPattern(level int64) *HierarchyIdPattern {
+	for i := 0; i < len(Patterns); i++ {
+		if Patterns[i].Min <= level && Patterns[i].Max >= level {
+			return &Patterns[i]
+		}
+	}
+
+	return nil
+}
+
 // Decode values a string representation of the hierarchyid data type for a pattern
 func decodeValue(pattern string, bin string) (int64, error) {
 	var binValue string = ""
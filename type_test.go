@@ -323,3 +323,156 @@ func TestToString(t *testing.T) {
 		}
 	}
 }
+
+type TestCompareStruct struct {
+	a      []int64
+	b      []int64
+	output int
+}
+
+var TestCompareData []TestCompareStruct = []TestCompareStruct{
+	{[]int64{}, []int64{}, 0},
+	{[]int64{1}, []int64{1}, 0},
+	{[]int64{1}, []int64{2}, -1},
+	{[]int64{2}, []int64{1}, 1},
+	{[]int64{-1}, []int64{1}, -1},
+	{[]int64{}, []int64{1}, 0},
+	{[]int64{1}, []int64{1, 2}, 0},
+	{[]int64{1, 2}, []int64{1}, 0},
+	{[]int64{1, 2, 3}, []int64{1, 3}, -1},
+}
+
+func TestCompare(t *testing.T) {
+	for _, d := range TestCompareData {
+		result := Compare(d.a, d.b)
+
+		if result != d.output {
+			t.Errorf("Expected %v compared to %v to return %v, got %v", d.a, d.b, d.output, result)
+		}
+	}
+}
+
+var TestCompareDepthFirstData []TestCompareStruct = []TestCompareStruct{
+	{[]int64{}, []int64{}, 0},
+	{[]int64{1}, []int64{1}, 0},
+	{[]int64{1}, []int64{2}, -1},
+	{[]int64{2}, []int64{1}, 1},
+	{[]int64{}, []int64{1}, -1},
+	{[]int64{1}, []int64{1, 2}, -1},
+	{[]int64{1, 2}, []int64{1}, 1},
+	{[]int64{1, 2, 3}, []int64{1, 3}, -1},
+	{[]int64{1, 3}, []int64{1, 2, 3}, 1},
+}
+
+func TestCompareDepthFirst(t *testing.T) {
+	for _, d := range TestCompareDepthFirstData {
+		result := CompareDepthFirst(d.a, d.b)
+
+		if result != d.output {
+			t.Errorf("Expected %v compared to %v to return %v, got %v", d.a, d.b, d.output, result)
+		}
+	}
+}
+
+type TestLowestCommonAncestorStruct struct {
+	input  [][]int64
+	output []int64
+}
+
+var TestLowestCommonAncestorData []TestLowestCommonAncestorStruct = []TestLowestCommonAncestorStruct{
+	{[][]int64{}, []int64{}},
+	{[][]int64{{1, 2, 3}}, []int64{1, 2, 3}},
+	{[][]int64{{1, 2, 3}, {1, 2, 5, 1}}, []int64{1, 2}},
+	{[][]int64{{1, 2, 3}, {1, 2}}, []int64{1, 2}},
+	{[][]int64{{1, 2, 3}, {1, 2, 5}, {1, 3}}, []int64{1}},
+	{[][]int64{{1}, {2}}, []int64{}},
+}
+
+func TestLowestCommonAncestor(t *testing.T) {
+	for _, d := range TestLowestCommonAncestorData {
+		result := LowestCommonAncestor(d.input...)
+
+		if !slices.Equal(result, d.output) {
+			t.Errorf("Expected %v to return %v, got %v", d.input, d.output, result)
+		}
+	}
+}
+
+type TestRelativeStruct struct {
+	from     []int64
+	to       []int64
+	relative string
+	distance int
+}
+
+var TestRelativeData []TestRelativeStruct = []TestRelativeStruct{
+	{[]int64{1, 2}, []int64{1, 2}, "./", 0},
+	{[]int64{1, 2}, []int64{1, 3, 1}, "../3/1/", 3},
+	{[]int64{1, 2, 7}, []int64{1, 3, 1}, "../../3/1/", 4},
+	{[]int64{1}, []int64{1, 2}, "2/", 1},
+	{[]int64{1, 2}, []int64{1}, "../", 1},
+	{[]int64{}, []int64{-1, 4}, "-1/4/", 2},
+}
+
+func TestRelative(t *testing.T) {
+	for _, d := range TestRelativeData {
+		result := Relative(d.from, d.to)
+		if result != d.relative {
+			t.Errorf("Expected %v to %v to return %v, got %v", d.from, d.to, d.relative, result)
+		}
+
+		distance := Distance(d.from, d.to)
+		if distance != d.distance {
+			t.Errorf("Expected distance from %v to %v to be %v, got %v", d.from, d.to, d.distance, distance)
+		}
+
+		resolved, err := Resolve(d.from, result)
+		if err != nil || !slices.Equal(resolved, d.to) {
+			t.Errorf("Expected %v resolved from %v to return %v, got %v %v", result, d.from, d.to, resolved, err)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	result, err := Resolve([]int64{1, 2}, "/3/./4/")
+	if err != nil || !slices.Equal(result, []int64{3, 4}) {
+		t.Errorf("Expected absolute path to return /3/4/, got %v %v", result, err)
+	}
+
+	_, err = Resolve([]int64{1}, "../../")
+	if err == nil {
+		t.Error("Expected error when going above the root")
+	}
+
+	_, err = Resolve([]int64{1}, "../a/")
+	if err == nil {
+		t.Error("Expected error for invalid level")
+	}
+}
+
+func TestStripPrefixJoin(t *testing.T) {
+	suffix, ok := StripPrefix([]int64{1, 2, 3, 4}, []int64{1, 2})
+	if !ok || !slices.Equal(suffix, []int64{3, 4}) {
+		t.Errorf("Expected /3/4/, got %v %v", suffix, ok)
+	}
+
+	suffix, ok = StripPrefix([]int64{1, 2}, []int64{1, 2})
+	if !ok || len(suffix) != 0 {
+		t.Errorf("Expected /, got %v %v", suffix, ok)
+	}
+
+	_, ok = StripPrefix([]int64{1, 2}, []int64{1, 3})
+	if ok {
+		t.Error("Expected /1/3/ to not be a prefix of /1/2/")
+	}
+
+	_, ok = StripPrefix([]int64{1}, []int64{1, 2})
+	if ok {
+		t.Error("Expected /1/2/ to not be a prefix of /1/")
+	}
+
+	var joined = Join([]int64{1, 2}, []int64{3, 4})
+	if !slices.Equal(joined, []int64{1, 2, 3, 4}) {
+		t.Errorf("Expected /1/2/3/4/, got %v", joined)
+	}
+}